@@ -0,0 +1,266 @@
+package aircraft
+
+// Representative POH figures, used when the user has no profile of their own
+var builtin = map[string]Profile{
+	"C172": Profile{
+		Id:         "C172",
+		Name:       "Cessna 172S",
+		DefaultRpm: 2400,
+		UsableGal:  53,
+		TaxiGal:    1.4,
+		ClimbTable: PhaseTable{
+			AltFt:  []float64{0, 4000, 8000, 12000},
+			IsaDev: []float64{-20, 0, 20},
+			Fpm: [][]float64{
+				{770, 730, 690},
+				{569, 529, 489},
+				{367, 327, 287},
+				{166, 126, 86},
+			},
+			Tas: [][]float64{
+				{71, 74, 77},
+				{77, 80, 83},
+				{82, 86, 89},
+				{88, 92, 95},
+			},
+			Gph: [][]float64{
+				{11.1, 10.5, 9.9},
+				{10, 9.5, 8.9},
+				{8.9, 8.4, 7.9},
+				{7.8, 7.3, 6.9},
+			},
+		},
+		CruiseTables: []CruiseTable{
+			CruiseTable{
+				IsaDev: -20,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{91, 97, 103, 108, 113},
+					{93, 99, 105, 110, 115},
+					{95, 101, 107, 112, 117},
+					{96, 103, 109, 114, 119},
+					{97, 104, 110, 115, 120},
+					{97, 104, 110, 115, 119},
+					{96, 103, 108, 113, 117},
+				},
+				Gph: [][]float64{
+					{6.9, 7.6, 8.4, 9.3, 10.3},
+					{6.7, 7.4, 8.2, 9.1, 10.1},
+					{6.5, 7.2, 7.9, 8.8, 9.7},
+					{6.3, 6.9, 7.6, 8.4, 9.3},
+					{6.1, 6.7, 7.3, 8, 8.9},
+					{5.9, 6.4, 7, 7.7, 8.4},
+					{5.7, 6.2, 6.7, 7.3, 8},
+				},
+			},
+			CruiseTable{
+				IsaDev: 0,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{92, 98, 104, 109, 114},
+					{94, 100, 106, 111, 116},
+					{96, 102, 108, 113, 118},
+					{97, 104, 110, 115, 120},
+					{98, 105, 111, 116, 121},
+					{98, 105, 111, 116, 120},
+					{97, 104, 109, 114, 118},
+				},
+				Gph: [][]float64{
+					{6.6, 7.3, 8.1, 9, 10},
+					{6.4, 7.1, 7.9, 8.8, 9.8},
+					{6.2, 6.9, 7.6, 8.5, 9.4},
+					{6, 6.6, 7.3, 8.1, 9},
+					{5.8, 6.4, 7, 7.7, 8.6},
+					{5.6, 6.1, 6.7, 7.4, 8.1},
+					{5.4, 5.9, 6.4, 7, 7.7},
+				},
+			},
+			CruiseTable{
+				IsaDev: 20,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{93, 99, 105, 110, 115},
+					{95, 101, 107, 112, 117},
+					{97, 103, 109, 114, 119},
+					{98, 105, 111, 116, 121},
+					{99, 106, 112, 117, 122},
+					{99, 106, 112, 117, 121},
+					{98, 105, 110, 115, 119},
+				},
+				Gph: [][]float64{
+					{6.3, 7, 7.8, 8.7, 9.7},
+					{6.1, 6.8, 7.6, 8.5, 9.5},
+					{5.9, 6.6, 7.3, 8.2, 9.1},
+					{5.7, 6.3, 7, 7.8, 8.7},
+					{5.5, 6.1, 6.7, 7.4, 8.3},
+					{5.3, 5.8, 6.4, 7.1, 7.8},
+					{5.1, 5.6, 6.1, 6.7, 7.4},
+				},
+			},
+		},
+		DescentTable: PhaseTable{
+			AltFt:  []float64{0, 12000},
+			IsaDev: []float64{0},
+			Fpm: [][]float64{
+				{500},
+				{500},
+			},
+			Tas: [][]float64{
+				{105},
+				{126},
+			},
+			Gph: [][]float64{
+				{6.5},
+				{5.2},
+			},
+		},
+		WeightBalance: &WeightBalance{
+			EmptyLbs: 1680,
+			EmptyArm: 39.3,
+			Stations: []Station{
+				{Name: "Front", Arm: 37, MaxLbs: 400},
+				{Name: "Rear", Arm: 73, MaxLbs: 400},
+				{Name: "Baggage1", Arm: 95, MaxLbs: 120},
+				{Name: "Baggage2", Arm: 123, MaxLbs: 50},
+				{Name: "Fuel", Arm: 48, Fuel: true},
+			},
+			Envelopes: []Envelope{
+				{Name: "Normal", Points: [][2]float64{{35, 1500}, {35, 1950}, {39.5, 2550}, {47.3, 2550}, {47.3, 1500}}},
+				{Name: "Utility", Points: [][2]float64{{35, 1500}, {35, 1950}, {37.5, 2200}, {40.5, 2200}, {40.5, 1500}}},
+			},
+		},
+	},
+	"PA28": Profile{
+		Id:         "PA28",
+		Name:       "Piper PA-28-181 Archer",
+		DefaultRpm: 2500,
+		UsableGal:  48,
+		TaxiGal:    1.2,
+		ClimbTable: PhaseTable{
+			AltFt:  []float64{0, 4000, 8000, 12000},
+			IsaDev: []float64{-20, 0, 20},
+			Fpm: [][]float64{
+				{710, 670, 630},
+				{525, 485, 445},
+				{340, 300, 260},
+				{156, 116, 76},
+			},
+			Tas: [][]float64{
+				{73, 76, 79},
+				{79, 82, 85},
+				{85, 88, 92},
+				{90, 94, 98},
+			},
+			Gph: [][]float64{
+				{10.6, 10, 9.4},
+				{9.5, 9, 8.5},
+				{8.5, 8, 7.5},
+				{7.4, 7, 6.6},
+			},
+		},
+		CruiseTables: []CruiseTable{
+			CruiseTable{
+				IsaDev: -20,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{94, 100, 106, 111, 116},
+					{96, 102, 108, 113, 118},
+					{98, 104, 110, 115, 120},
+					{99, 106, 112, 117, 122},
+					{100, 107, 113, 118, 123},
+					{100, 107, 113, 118, 122},
+					{99, 106, 111, 116, 120},
+				},
+				Gph: [][]float64{
+					{7.4, 8.2, 9, 10, 11.1},
+					{7.2, 8, 8.8, 9.8, 10.9},
+					{7, 7.8, 8.5, 9.5, 10.5},
+					{6.8, 7.4, 8.2, 9, 10},
+					{6.6, 7.2, 7.9, 8.6, 9.6},
+					{6.3, 6.9, 7.5, 8.3, 9},
+					{6.1, 6.7, 7.2, 7.9, 8.6},
+				},
+			},
+			CruiseTable{
+				IsaDev: 0,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{95, 101, 107, 112, 117},
+					{97, 103, 109, 114, 119},
+					{99, 105, 111, 116, 121},
+					{100, 107, 113, 118, 123},
+					{101, 108, 114, 119, 124},
+					{101, 108, 114, 119, 123},
+					{100, 107, 112, 117, 121},
+				},
+				Gph: [][]float64{
+					{7.1, 7.9, 8.7, 9.7, 10.8},
+					{6.9, 7.7, 8.5, 9.5, 10.6},
+					{6.7, 7.5, 8.2, 9.2, 10.2},
+					{6.5, 7.1, 7.9, 8.7, 9.7},
+					{6.3, 6.9, 7.6, 8.3, 9.3},
+					{6, 6.6, 7.2, 8, 8.7},
+					{5.8, 6.4, 6.9, 7.6, 8.3},
+				},
+			},
+			CruiseTable{
+				IsaDev: 20,
+				AltFt:  []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+				Rpm:    []float64{2200, 2300, 2400, 2500, 2600},
+				Tas: [][]float64{
+					{96, 102, 108, 113, 118},
+					{98, 104, 110, 115, 120},
+					{100, 106, 112, 117, 122},
+					{101, 108, 114, 119, 124},
+					{102, 109, 115, 120, 125},
+					{102, 109, 115, 120, 124},
+					{101, 108, 113, 118, 122},
+				},
+				Gph: [][]float64{
+					{6.8, 7.6, 8.4, 9.4, 10.5},
+					{6.6, 7.4, 8.2, 9.2, 10.3},
+					{6.4, 7.2, 7.9, 8.9, 9.9},
+					{6.2, 6.8, 7.6, 8.4, 9.4},
+					{6, 6.6, 7.3, 8, 9},
+					{5.7, 6.3, 6.9, 7.7, 8.4},
+					{5.5, 6.1, 6.6, 7.3, 8},
+				},
+			},
+		},
+		DescentTable: PhaseTable{
+			AltFt:  []float64{0, 12000},
+			IsaDev: []float64{0},
+			Fpm: [][]float64{
+				{500},
+				{500},
+			},
+			Tas: [][]float64{
+				{110},
+				{132},
+			},
+			Gph: [][]float64{
+				{7},
+				{5.6},
+			},
+		},
+		WeightBalance: &WeightBalance{
+			EmptyLbs: 1620,
+			EmptyArm: 86.4,
+			Stations: []Station{
+				{Name: "Front", Arm: 80.5, MaxLbs: 400},
+				{Name: "Rear", Arm: 118.1, MaxLbs: 400},
+				{Name: "Baggage", Arm: 142.8, MaxLbs: 200},
+				{Name: "Fuel", Arm: 95, Fuel: true},
+			},
+			Envelopes: []Envelope{
+				{Name: "Normal", Points: [][2]float64{{82, 1200}, {82, 1950}, {88.6, 2550}, {93, 2550}, {93, 1200}}},
+				{Name: "Utility", Points: [][2]float64{{82, 1200}, {82, 1950}, {83.2, 2130}, {93, 2130}, {93, 1200}}},
+			},
+		},
+	},
+}
@@ -0,0 +1,66 @@
+package aircraft
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"sort"
+)
+
+// Larger deviations mean the compass needs swinging, not a card
+const max_deviation_deg = 30
+
+// Compass deviation card entry, e.g., FOR 030 STEER 032
+type Deviation struct {
+	For   float64 `json:"for"`   // Magnetic heading
+	Steer float64 `json:"steer"` // Compass heading
+}
+
+// Deviation card, interpolated between entries and around north
+type DeviationCard []Deviation
+
+func (d DeviationCard) Validate() error {
+	seen := map[float64]bool{}
+	for _, e := range d {
+		if e.For < 0 || e.For >= 360 || e.Steer < 0 || e.Steer >= 360 {
+			return fmt.Errorf("Headings must be from 0 to 359, not for %.0f steer %.0f", e.For, e.Steer)
+		} else if seen[e.For] {
+			return fmt.Errorf("Duplicate entry for %.0f", e.For)
+		} else if math.Abs(e.deviation()) > max_deviation_deg {
+			return fmt.Errorf("Deviation for %.0f exceeds %d degrees", e.For, max_deviation_deg)
+		}
+		seen[e.For] = true
+	}
+	return nil
+}
+
+// Compass minus magnetic heading, degrees
+func (e Deviation) deviation() float64 {
+	return geo.Wrap360(e.Steer-e.For+180) - 180
+}
+
+// Compass heading to steer for a magnetic heading, unchanged by an empty card
+func (d DeviationCard) Compass(magnetic float64) float64 {
+	if len(d) == 0 {
+		return geo.Wrap360(magnetic)
+	}
+	entries := append(DeviationCard{}, d...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].For < entries[j].For })
+	magnetic = geo.Wrap360(magnetic)
+	// Entries either side, wrapping past north
+	hi := sort.Search(len(entries), func(i int) bool { return entries[i].For >= magnetic })
+	lo := hi - 1
+	if hi == len(entries) {
+		hi = 0
+	}
+	if lo < 0 {
+		lo = len(entries) - 1
+	}
+	span := geo.Wrap360(entries[hi].For - entries[lo].For)
+	dev := entries[lo].deviation()
+	if span != 0 {
+		frac := geo.Wrap360(magnetic-entries[lo].For) / span
+		dev += frac * (entries[hi].deviation() - dev)
+	}
+	return geo.Wrap360(magnetic + dev)
+}
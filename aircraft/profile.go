@@ -0,0 +1,257 @@
+package aircraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Aircraft profiles (<ID>.json) in this directory, relative to the user's home
+// directory, take precedence over the built-in profiles
+const profile_dir = ".flight/aircraft"
+
+// Speed and fuel flow for a phase of flight
+type Performance struct {
+	Tas float64 // Knots
+	Gph float64 // Gallons per hour
+	Fpm float64 // Climb or descent rate, zero in cruise
+}
+
+// Climb or descent performance by pressure altitude and ISA deviation (C)
+type PhaseTable struct {
+	AltFt  []float64   `json:"alt_ft"`
+	IsaDev []float64   `json:"isa_dev"`
+	Fpm    [][]float64 `json:"fpm"` // Indexed [alt][isa_dev], as are tas and gph
+	Tas    [][]float64 `json:"tas"`
+	Gph    [][]float64 `json:"gph"`
+}
+
+func (t PhaseTable) tables() []Table2D {
+	tables := []Table2D{}
+	for _, v := range [][][]float64{t.Fpm, t.Tas, t.Gph} {
+		tables = append(tables, Table2D{Rows: t.AltFt, Cols: t.IsaDev, Values: v})
+	}
+	return tables
+}
+
+func (t PhaseTable) At(altFt int, isaDev float64) (Performance, error) {
+	v := [3]float64{}
+	for i, table := range t.tables() {
+		var err error
+		if v[i], err = table.Lookup(float64(altFt), isaDev); err != nil {
+			return Performance{}, err
+		}
+	}
+	return Performance{Fpm: v[0], Tas: v[1], Gph: v[2]}, nil
+}
+
+// Cruise performance at one ISA deviation, by pressure altitude and rpm
+type CruiseTable struct {
+	IsaDev float64     `json:"isa_dev"`
+	AltFt  []float64   `json:"alt_ft"`
+	Rpm    []float64   `json:"rpm"`
+	Tas    [][]float64 `json:"tas"` // Indexed [alt][rpm], as is gph
+	Gph    [][]float64 `json:"gph"`
+}
+
+func (t CruiseTable) At(altFt int, rpm int) (Performance, error) {
+	tas, err := Table2D{Rows: t.AltFt, Cols: t.Rpm, Values: t.Tas}.Lookup(float64(altFt), float64(rpm))
+	if err != nil {
+		return Performance{}, err
+	}
+	gph, err := Table2D{Rows: t.AltFt, Cols: t.Rpm, Values: t.Gph}.Lookup(float64(altFt), float64(rpm))
+	if err != nil {
+		return Performance{}, err
+	}
+	return Performance{Tas: tas, Gph: gph}, nil
+}
+
+type Profile struct {
+	Id           string        `json:"id"`
+	Name         string        `json:"name"`
+	DefaultRpm   int           `json:"default_rpm"` // Used when a plan specifies no power setting
+	UsableGal    float64       `json:"usable_gal"`
+	TaxiGal      float64       `json:"taxi_gal"` // Start, taxi and run-up
+	ClimbTable   PhaseTable    `json:"climb"`
+	CruiseTables []CruiseTable `json:"cruise"` // Ascending ISA deviation
+	DescentTable PhaseTable    `json:"descent"`
+	// Optional, required only for weight and balance
+	WeightBalance *WeightBalance `json:"weight_balance,omitempty"`
+	// Optional, converts magnetic to compass headings
+	DeviationCard DeviationCard `json:"deviation,omitempty"`
+}
+
+func (p Profile) Climb(altFt int, isaDev float64) (Performance, error) {
+	perf, err := p.ClimbTable.At(altFt, isaDev)
+	if err != nil {
+		return Performance{}, fmt.Errorf("%s climb performance at %dft, ISA%+.0f: %s", p.Id, altFt, isaDev, err)
+	}
+	return perf, nil
+}
+
+// Cruise performance, interpolated between the tables for the ISA deviations
+// either side of isaDev
+func (p Profile) Cruise(altFt int, isaDev float64, rpm int) (Performance, error) {
+	e := func(err error) error {
+		return fmt.Errorf("%s cruise performance at %dft, ISA%+.0f, %d rpm: %s", p.Id, altFt, isaDev, rpm, err)
+	}
+	t := p.CruiseTables
+	if len(t) == 0 {
+		return Performance{}, e(errors.New("no cruise tables"))
+	}
+	devs := []float64{}
+	for _, c := range t {
+		devs = append(devs, c.IsaDev)
+	}
+	i, j, f, err := bracket(devs, isaDev)
+	if err != nil {
+		return Performance{}, e(err)
+	}
+	a, err := t[i].At(altFt, rpm)
+	if err != nil {
+		return Performance{}, e(err)
+	}
+	b, err := t[j].At(altFt, rpm)
+	if err != nil {
+		return Performance{}, e(err)
+	}
+	return Performance{Tas: lerp(a.Tas, b.Tas, f), Gph: lerp(a.Gph, b.Gph, f)}, nil
+}
+
+// ISA deviation limited to the range tabulated for every phase of flight, and
+// whether it had to be limited. Single point axes are constant and don't limit.
+func (p Profile) ClampIsaDev(isaDev float64) (float64, bool) {
+	devs := []float64{}
+	for _, c := range p.CruiseTables {
+		devs = append(devs, c.IsaDev)
+	}
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for _, axis := range [][]float64{p.ClimbTable.IsaDev, devs, p.DescentTable.IsaDev} {
+		if len(axis) > 1 {
+			lo, hi = math.Max(lo, axis[0]), math.Min(hi, axis[len(axis)-1])
+		}
+	}
+	clamped := math.Max(lo, math.Min(hi, isaDev))
+	return clamped, clamped != isaDev
+}
+
+func (p Profile) Descent(altFt int, isaDev float64) (Performance, error) {
+	perf, err := p.DescentTable.At(altFt, isaDev)
+	if err != nil {
+		return Performance{}, fmt.Errorf("%s descent performance at %dft, ISA%+.0f: %s", p.Id, altFt, isaDev, err)
+	}
+	return perf, nil
+}
+
+func (p Profile) Validate() error {
+	if len(p.Id) == 0 {
+		return errors.New("Aircraft profile is missing an id")
+	}
+	check := func(name string, tables []Table2D) error {
+		for _, t := range tables {
+			if err := t.Validate(); err != nil {
+				return fmt.Errorf("Aircraft profile %s %s table: %s", p.Id, name, err)
+			}
+		}
+		return nil
+	}
+	if err := check("climb", p.ClimbTable.tables()); err != nil {
+		return err
+	} else if err := check("descent", p.DescentTable.tables()); err != nil {
+		return err
+	} else if len(p.CruiseTables) == 0 {
+		return errors.New("Aircraft profile " + p.Id + " has no cruise tables")
+	}
+	for i, c := range p.CruiseTables {
+		if i > 0 && c.IsaDev <= p.CruiseTables[i-1].IsaDev {
+			return errors.New("Aircraft profile " + p.Id + " cruise tables must be in ascending ISA deviation")
+		}
+		err := check("cruise", []Table2D{
+			Table2D{Rows: c.AltFt, Cols: c.Rpm, Values: c.Tas},
+			Table2D{Rows: c.AltFt, Cols: c.Rpm, Values: c.Gph},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if p.WeightBalance != nil {
+		if err := p.WeightBalance.Validate(); err != nil {
+			return fmt.Errorf("Aircraft profile %s weight and balance: %s", p.Id, err)
+		}
+	}
+	if err := p.DeviationCard.Validate(); err != nil {
+		return fmt.Errorf("Aircraft profile %s deviation card: %s", p.Id, err)
+	}
+	return nil
+}
+
+// Standard atmosphere temperature, Celsius, below the tropopause
+func IsaTemp(altFt int) float64 {
+	return 15 - 1.98*float64(altFt)/1000
+}
+
+// Parses a JSON aircraft profile
+func Parse(r io.Reader) (Profile, error) {
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	p := Profile{}
+	if err := d.Decode(&p); err != nil {
+		return Profile{}, errors.New("Error parsing aircraft profile: " + err.Error())
+	}
+	p.Id = strings.ToUpper(p.Id)
+	if err := p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+func (p Profile) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// Path of the user's profile for an aircraft, whether or not it exists
+func ProfilePath(id string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, profile_dir, strings.ToUpper(id)+".json"), nil
+}
+
+// Loads an aircraft profile by type, e.g., C172, from the user's profile
+// directory or else from the built-in profiles
+func Load(id string) (Profile, error) {
+	if path, err := ProfilePath(id); err == nil {
+		if file, err := os.Open(path); err == nil {
+			defer file.Close()
+			p, err := Parse(file)
+			if err != nil {
+				return Profile{}, fmt.Errorf("%s: %s", path, err)
+			}
+			return p, nil
+		} else if !os.IsNotExist(err) {
+			return Profile{}, err
+		}
+	}
+	if p, exists := builtin[strings.ToUpper(id)]; exists {
+		return p, nil
+	}
+	ids := []string{}
+	for k, _ := range builtin {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	return Profile{}, fmt.Errorf("Unknown aircraft %s, expected a profile in ~/%s or one of: %s",
+		id, profile_dir, strings.Join(ids, ", "))
+}
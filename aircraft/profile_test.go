@@ -0,0 +1,85 @@
+package aircraft
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCruiseInterpolation(t *testing.T) {
+	c172 := builtin["C172"]
+	if err := c172.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	cold, err := c172.Cruise(6000, -20, 2400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standard, err := c172.Cruise(6000, 0, 2400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Halfway between the ISA-20 and ISA tables
+	between, err := c172.Cruise(6000, -10, 2400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(between.Tas-(cold.Tas+standard.Tas)/2) > 1e-9 || math.Abs(between.Gph-(cold.Gph+standard.Gph)/2) > 1e-9 {
+		t.Errorf("ISA-10: got %+v, want halfway between %+v and %+v", between, cold, standard)
+	}
+	// And within each table, between altitudes and rpm
+	corners := []Performance{}
+	for _, alt := range []int{6000, 8000} {
+		for _, rpm := range []int{2300, 2400} {
+			p, err := c172.Cruise(alt, 0, rpm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			corners = append(corners, p)
+		}
+	}
+	mid, err := c172.Cruise(7000, 0, 2350)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := (corners[0].Tas + corners[1].Tas + corners[2].Tas + corners[3].Tas) / 4
+	if math.Abs(mid.Tas-want) > 1e-9 {
+		t.Errorf("7000ft 2350 rpm: got %.2f kts, want %.2f", mid.Tas, want)
+	}
+}
+
+func TestCruiseOutsideTables(t *testing.T) {
+	c172 := builtin["C172"]
+	if _, err := c172.Cruise(6000, 25, 2400); err == nil {
+		t.Error("Expected an error for ISA+25 without clamping")
+	}
+	if _, err := c172.Cruise(14000, 0, 2400); err == nil {
+		t.Error("Expected an error above the tabulated altitudes")
+	}
+}
+
+func TestClampIsaDev(t *testing.T) {
+	for _, id := range []string{"C172", "PA28"} {
+		p := builtin[id]
+		for _, c := range []struct {
+			isaDev, want float64
+			clamped      bool
+		}{
+			{0, 0, false},
+			{-20, -20, false},
+			{15, 15, false},
+			{25, 20, true},
+			{-35, -20, true},
+		} {
+			got, clamped := p.ClampIsaDev(c.isaDev)
+			if got != c.want || clamped != c.clamped {
+				t.Errorf("%s ISA%+.0f: got %g, %v, want %g, %v", id, c.isaDev, got, clamped, c.want, c.clamped)
+			}
+			if _, err := p.Cruise(6000, got, p.DefaultRpm); err != nil {
+				t.Errorf("%s ISA%+.0f: %s", id, c.isaDev, err)
+			}
+			if _, err := p.Climb(6000, got); err != nil {
+				t.Errorf("%s ISA%+.0f: %s", id, c.isaDev, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package aircraft
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Values tabulated over two ascending axes, e.g., altitude by rpm. An axis
+// with a single point is treated as constant along that axis.
+type Table2D struct {
+	Rows   []float64
+	Cols   []float64
+	Values [][]float64 // Values[row][col]
+}
+
+func (t Table2D) Validate() error {
+	if len(t.Rows) == 0 || len(t.Cols) == 0 {
+		return errors.New("empty table axis")
+	} else if !sort.Float64sAreSorted(t.Rows) || !sort.Float64sAreSorted(t.Cols) {
+		return errors.New("table axes must be ascending")
+	} else if len(t.Values) != len(t.Rows) {
+		return fmt.Errorf("expected %d table rows, got %d", len(t.Rows), len(t.Values))
+	}
+	for i, r := range t.Values {
+		if len(r) != len(t.Cols) {
+			return fmt.Errorf("expected %d values in table row %d, got %d", len(t.Cols), i+1, len(r))
+		}
+	}
+	return nil
+}
+
+// Bilinear interpolation between the tabulated points surrounding (row, col)
+func (t Table2D) Lookup(row, col float64) (float64, error) {
+	r0, r1, rf, err := bracket(t.Rows, row)
+	if err != nil {
+		return 0, err
+	}
+	c0, c1, cf, err := bracket(t.Cols, col)
+	if err != nil {
+		return 0, err
+	}
+	a := lerp(t.Values[r0][c0], t.Values[r0][c1], cf)
+	b := lerp(t.Values[r1][c0], t.Values[r1][c1], cf)
+	return lerp(a, b, rf), nil
+}
+
+// Indices of the points surrounding v and its fraction of the way between them
+func bracket(axis []float64, v float64) (int, int, float64, error) {
+	if len(axis) == 1 {
+		return 0, 0, 0, nil
+	}
+	if v < axis[0] || v > axis[len(axis)-1] {
+		return 0, 0, 0, fmt.Errorf("%g is outside the tabulated range %g to %g", v, axis[0], axis[len(axis)-1])
+	}
+	i := sort.SearchFloat64s(axis, v)
+	if axis[i] == v {
+		return i, i, 0, nil
+	}
+	return i - 1, i, (v - axis[i-1]) / (axis[i] - axis[i-1]), nil
+}
+
+func lerp(a, b, f float64) float64 {
+	return a + f*(b-a)
+}
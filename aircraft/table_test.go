@@ -0,0 +1,78 @@
+package aircraft
+
+import (
+	"math"
+	"testing"
+)
+
+var testTable = Table2D{
+	Rows: []float64{0, 4000, 8000},
+	Cols: []float64{2200, 2400},
+	Values: [][]float64{
+		{100, 110},
+		{104, 116},
+		{108, 122},
+	},
+}
+
+func TestLookup(t *testing.T) {
+	if err := testTable.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		row, col, want float64
+	}{
+		// Tabulated points, including the corners
+		{0, 2200, 100},
+		{8000, 2400, 122},
+		{4000, 2400, 116},
+		// Along one axis
+		{2000, 2200, 102},
+		{4000, 2300, 110},
+		// Bilinear, between four points
+		{6000, 2300, 112.5},
+		{1000, 2350, 108.875},
+	} {
+		got, err := testTable.Lookup(c.row, c.col)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("Lookup(%g, %g): got %g, want %g", c.row, c.col, got, c.want)
+		}
+	}
+}
+
+func TestLookupOutsideTable(t *testing.T) {
+	for _, c := range [][2]float64{{-1, 2200}, {8001, 2200}, {4000, 2100}, {4000, 2500}} {
+		if v, err := testTable.Lookup(c[0], c[1]); err == nil {
+			t.Errorf("Lookup(%g, %g): got %g, want an error", c[0], c[1], v)
+		}
+	}
+}
+
+func TestLookupSinglePointAxis(t *testing.T) {
+	table := Table2D{Rows: []float64{0, 10000}, Cols: []float64{0}, Values: [][]float64{{500}, {700}}}
+	if err := table.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// Constant along the single point axis, whatever the value
+	for _, col := range []float64{-30, 0, 25} {
+		if v, err := table.Lookup(5000, col); err != nil || v != 600 {
+			t.Errorf("Lookup(5000, %g): got %g, %v, want 600", col, v, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, table := range []Table2D{
+		{},
+		{Rows: []float64{4000, 0}, Cols: []float64{0}, Values: [][]float64{{1}, {2}}},
+		{Rows: []float64{0, 4000}, Cols: []float64{0}, Values: [][]float64{{1}}},
+		{Rows: []float64{0}, Cols: []float64{0, 1}, Values: [][]float64{{1}}},
+	} {
+		if err := table.Validate(); err == nil {
+			t.Errorf("Validate(%v): expected an error", table)
+		}
+	}
+}
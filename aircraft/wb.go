@@ -0,0 +1,149 @@
+package aircraft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const default_fuel_lbs_per_gal = 6.0
+
+// A location loads may be placed, e.g., a seat row or baggage area
+type Station struct {
+	Name   string  `json:"name"`
+	Arm    float64 `json:"arm"`     // Inches aft of datum
+	MaxLbs float64 `json:"max_lbs"` // Zero if unlimited, ignored for fuel
+	Fuel   bool    `json:"fuel,omitempty"`
+}
+
+// CG limits as a polygon of (arm, lbs) vertices, e.g., the normal category
+type Envelope struct {
+	Name   string       `json:"name"`
+	Points [][2]float64 `json:"points"`
+}
+
+type WeightBalance struct {
+	EmptyLbs      float64    `json:"empty_lbs"`
+	EmptyArm      float64    `json:"empty_arm"`
+	FuelLbsPerGal float64    `json:"fuel_lbs_per_gal,omitempty"` // Avgas if zero
+	Stations      []Station  `json:"stations"`
+	Envelopes     []Envelope `json:"envelopes"`
+}
+
+// Weight and moment, in pounds and pound-inches
+type Mass struct {
+	Lbs    float64
+	Moment float64
+}
+
+func (m Mass) Cg() float64 {
+	return m.Moment / m.Lbs
+}
+
+func (m Mass) Add(lbs, arm float64) Mass {
+	return Mass{Lbs: m.Lbs + lbs, Moment: m.Moment + lbs*arm}
+}
+
+func (wb WeightBalance) FuelLbs(gal float64) float64 {
+	if wb.FuelLbsPerGal == 0 {
+		return gal * default_fuel_lbs_per_gal
+	}
+	return gal * wb.FuelLbsPerGal
+}
+
+func (wb WeightBalance) Empty() Mass {
+	return Mass{}.Add(wb.EmptyLbs, wb.EmptyArm)
+}
+
+func (wb WeightBalance) Station(name string) (Station, error) {
+	for _, s := range wb.Stations {
+		if strings.EqualFold(s.Name, name) {
+			return s, nil
+		}
+	}
+	return Station{}, errors.New("Unknown station " + name)
+}
+
+// Fuel station, loaded in gallons rather than pounds
+func (wb WeightBalance) FuelStation() (Station, error) {
+	for _, s := range wb.Stations {
+		if s.Fuel {
+			return s, nil
+		}
+	}
+	return Station{}, errors.New("No fuel station")
+}
+
+// Adds the load at a station, in gallons for fuel and pounds otherwise
+func (wb WeightBalance) Load(m Mass, s Station, load float64) Mass {
+	if s.Fuel {
+		return m.Add(wb.FuelLbs(load), s.Arm)
+	}
+	return m.Add(load, s.Arm)
+}
+
+// Point in polygon, with points on the boundary inside
+func (e Envelope) Contains(m Mass) bool {
+	x, y := m.Cg(), m.Lbs
+	inside := false
+	p := e.Points
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		xi, yi, xj, yj := p[i][0], p[i][1], p[j][0], p[j][1]
+		if onSegment(x, y, xi, yi, xj, yj) {
+			return true
+		}
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func onSegment(x, y, x1, y1, x2, y2 float64) bool {
+	const epsilon = 1e-9
+	cross := (x-x1)*(y2-y1) - (y-y1)*(x2-x1)
+	if cross > epsilon || cross < -epsilon {
+		return false
+	}
+	return x >= minFloat(x1, x2)-epsilon && x <= maxFloat(x1, x2)+epsilon &&
+		y >= minFloat(y1, y2)-epsilon && y <= maxFloat(y1, y2)+epsilon
+}
+
+func (wb WeightBalance) Validate() error {
+	if wb.EmptyLbs <= 0 {
+		return errors.New("empty weight must be positive")
+	} else if len(wb.Envelopes) == 0 {
+		return errors.New("no CG envelopes")
+	}
+	fuel := 0
+	for _, s := range wb.Stations {
+		if len(s.Name) == 0 || strings.ContainsAny(s.Name, " \t=") {
+			return fmt.Errorf("invalid station name \"%s\", must be non-empty without spaces or '='", s.Name)
+		} else if s.Fuel {
+			fuel++
+		}
+	}
+	if fuel > 1 {
+		return errors.New("more than one fuel station")
+	}
+	for _, e := range wb.Envelopes {
+		if len(e.Points) < 3 {
+			return fmt.Errorf("%s envelope requires at least 3 points", e.Name)
+		}
+	}
+	return nil
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+package aircraft
+
+import (
+	"testing"
+)
+
+func massAt(cg, lbs float64) Mass {
+	return Mass{}.Add(lbs, cg)
+}
+
+func TestEnvelopeContains(t *testing.T) {
+	envelopes := map[string]Envelope{}
+	for _, e := range builtin["C172"].WeightBalance.Envelopes {
+		envelopes[e.Name] = e
+	}
+	for _, c := range []struct {
+		envelope string
+		cg, lbs  float64
+		want     bool
+	}{
+		// Normal category
+		{"Normal", 42, 2200, true},
+		{"Normal", 48, 2000, false},    // Aft
+		{"Normal", 40, 2600, false},    // Heavy
+		{"Normal", 40, 1400, false},    // Light
+		{"Normal", 36, 2300, false},    // Forward of the sloped limit
+		{"Normal", 47.31, 2000, false}, // Just aft
+		{"Normal", 35, 1500, true},     // Vertices
+		{"Normal", 35, 1950, true},
+		{"Normal", 39.5, 2550, true},
+		{"Normal", 47.3, 2550, true},
+		{"Normal", 47.3, 1500, true},
+		{"Normal", 37.25, 2250, true}, // Edges
+		{"Normal", 43.4, 2550, true},
+		{"Normal", 47.3, 2000, true},
+		{"Normal", 41, 1500, true},
+		{"Normal", 35, 1700, true},
+		// Utility category
+		{"Utility", 38, 2000, true},
+		{"Utility", 42, 2000, false}, // Normal only
+		{"Utility", 39, 2300, false},
+		{"Utility", 35.5, 2150, false}, // Forward of the sloped limit
+		{"Utility", 37.5, 2200, true},  // Vertices
+		{"Utility", 40.5, 1500, true},
+		{"Utility", 36.25, 2075, true}, // Edges
+		{"Utility", 39, 2200, true},
+		{"Utility", 40.5, 1800, true},
+	} {
+		e, exists := envelopes[c.envelope]
+		if !exists {
+			t.Fatalf("No %s envelope", c.envelope)
+		}
+		if got := e.Contains(massAt(c.cg, c.lbs)); got != c.want {
+			t.Errorf("%s at %.2f in, %.0f lbs: got %t, want %t", c.envelope, c.cg, c.lbs, got, c.want)
+		}
+	}
+}
+
+func TestOnSegment(t *testing.T) {
+	if !onSegment(1, 1, 0, 0, 2, 2) || !onSegment(0, 0, 0, 0, 2, 2) {
+		t.Error("Expected points on the segment")
+	}
+	// Beyond the end of the segment, and beside it
+	if onSegment(3, 3, 0, 0, 2, 2) || onSegment(1, 1.1, 0, 0, 2, 2) {
+		t.Error("Expected points off the segment")
+	}
+}
@@ -0,0 +1,79 @@
+package aviationweather
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Plumbing shared by the METAR, TAF and PIREP providers backed by the
+// aviationweather.gov data API
+// See https://aviationweather.gov/data/api/
+
+// Common to every XML response, embedded in the response type of each product
+type Response struct {
+	Errors []string `xml:"errors>error"`
+}
+
+func (r Response) serviceErrors() []string {
+	return r.Errors
+}
+
+type response interface {
+	serviceErrors() []string
+}
+
+// Fetches the result of a query, empty if nothing matched. The service name,
+// e.g., METAR, identifies the product in errors.
+func Fetch(client *http.Client, queryUrl string, service string) ([]byte, error) {
+	resp, err := client.Get(queryUrl)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return []byte{}, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return []byte{}, fmt.Errorf("%s service returned %s", service, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, err
+	}
+	return body, nil
+}
+
+// Parses an XML result into v, a pointer to a type embedding Response
+func Unmarshal(xmlBody []byte, service string, v response) error {
+	if err := xml.Unmarshal(xmlBody, v); err != nil {
+		return fmt.Errorf("%s: got \n%s", err, xmlBody)
+	}
+	if errs := v.serviceErrors(); len(errs) != 0 {
+		return errors.New("error(s) from " + service + " service: " + strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Indexes of the latest of n reports for each station, in response order.
+// Report times are RFC 3339 UTC, so they order as strings.
+func MostRecent(n int, report func(i int) (station, time string)) []int {
+	latest := make(map[string]int)
+	times := make([]string, n)
+	for i := 0; i < n; i++ {
+		station, time := report(i)
+		times[i] = time
+		if j, exists := latest[station]; !exists || time > times[j] {
+			latest[station] = i
+		}
+	}
+	indexes := []int{}
+	for _, i := range latest {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
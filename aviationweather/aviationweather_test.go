@@ -0,0 +1,75 @@
+package aviationweather
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(t *testing.T, status int, body string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestFetch(t *testing.T) {
+	s := serve(t, http.StatusOK, "<response/>")
+	if body, err := Fetch(s.Client(), s.URL, "METAR"); err != nil || string(body) != "<response/>" {
+		t.Errorf("Got %q, %v", body, err)
+	}
+	s = serve(t, http.StatusNoContent, "")
+	if body, err := Fetch(s.Client(), s.URL, "METAR"); err != nil || len(body) != 0 {
+		t.Errorf("No content: got %q, %v", body, err)
+	}
+	s = serve(t, http.StatusBadGateway, "")
+	if _, err := Fetch(s.Client(), s.URL, "TAF"); err == nil || !strings.HasPrefix(err.Error(), "TAF service returned 502") {
+		t.Errorf("Bad gateway: got %v", err)
+	}
+}
+
+type testResponse struct {
+	Response
+	Ids []string `xml:"data>report>id"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	var r testResponse
+	err := Unmarshal([]byte("<response><data><report><id>KBDU</id></report><report><id>KDEN</id></report></data></response>"), "PIREP", &r)
+	if err != nil || strings.Join(r.Ids, ",") != "KBDU,KDEN" {
+		t.Errorf("Got %v, %v", r.Ids, err)
+	}
+
+	r = testResponse{}
+	err = Unmarshal([]byte("<response><errors><error>Bad bbox</error><error>Bad age</error></errors></response>"), "PIREP", &r)
+	if err == nil || err.Error() != "error(s) from PIREP service: Bad bbox, Bad age" {
+		t.Errorf("Service errors: got %v", err)
+	}
+
+	if err := Unmarshal([]byte("<response>"), "PIREP", &testResponse{}); err == nil {
+		t.Error("Expected an error for invalid XML")
+	}
+}
+
+func TestMostRecent(t *testing.T) {
+	reports := [][2]string{
+		{"KBDU", "2026-10-18T16:55:00Z"},
+		{"KDEN", "2026-10-18T17:53:00Z"},
+		{"KBDU", "2026-10-18T17:55:00Z"},
+		{"KAPA", "2026-10-18T17:50:00Z"},
+		{"KDEN", "2026-10-18T16:53:00Z"},
+	}
+	got := MostRecent(len(reports), func(i int) (string, string) {
+		return reports[i][0], reports[i][1]
+	})
+	if fmt.Sprint(got) != "[1 2 3]" {
+		t.Errorf("Got %v, want the latest for each station in response order", got)
+	}
+	if got := MostRecent(0, nil); len(got) != 0 {
+		t.Errorf("Got %v for no reports", got)
+	}
+}
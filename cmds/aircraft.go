@@ -0,0 +1,61 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/aircraft"
+	"os"
+	"strconv"
+)
+
+func AircraftCmd(cmd CommandEntry, argv []string) error {
+	if len(argv) < 1 || len(argv) > 4 {
+		return cmd.getUsageError()
+	}
+	ac, err := aircraft.Load(argv[0])
+	if err != nil {
+		return err
+	}
+	if len(argv) == 1 {
+		// The profile itself, as a starting point for a customized copy
+		if path, err := aircraft.ProfilePath(ac.Id); err == nil {
+			fmt.Fprintf(os.Stderr, "Save as %s to customize\n", path)
+		}
+		return ac.Write(os.Stdout)
+	}
+
+	alt, err := strconv.Atoi(argv[1])
+	if err != nil {
+		return errors.New("Invalid altitude: " + argv[1])
+	}
+	isaDev := 0.0
+	if len(argv) > 2 {
+		if isaDev, err = strconv.ParseFloat(argv[2], 64); err != nil {
+			return errors.New("Invalid ISA deviation, e.g., +10: " + argv[2])
+		}
+	}
+	rpm := ac.DefaultRpm
+	if len(argv) > 3 {
+		if rpm, err = strconv.Atoi(argv[3]); err != nil {
+			return errors.New("Invalid rpm: " + argv[3])
+		}
+	}
+
+	fmt.Printf("%s at %dft, ISA%+.0f (%.0f C)\n\n", ac.Name, alt, isaDev, aircraft.IsaTemp(alt)+isaDev)
+	if perf, err := ac.Climb(alt, isaDev); err != nil {
+		fmt.Println("  Climb:  " + err.Error())
+	} else {
+		fmt.Printf("  Climb:  %d fpm, %d kts TAS, %.1f gph\n", round(perf.Fpm), round(perf.Tas), perf.Gph)
+	}
+	if perf, err := ac.Cruise(alt, isaDev, rpm); err != nil {
+		fmt.Println(" Cruise:  " + err.Error())
+	} else {
+		fmt.Printf(" Cruise:  %d rpm, %d kts TAS, %.1f gph\n", rpm, round(perf.Tas), perf.Gph)
+	}
+	if perf, err := ac.Descent(alt, isaDev); err != nil {
+		fmt.Println("Descent:  " + err.Error())
+	} else {
+		fmt.Printf("Descent:  %d fpm, %d kts TAS, %.1f gph\n", round(perf.Fpm), round(perf.Tas), perf.Gph)
+	}
+	return nil
+}
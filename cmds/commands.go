@@ -3,6 +3,8 @@ package cmds
 import (
 	"errors"
 	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"os"
 	"strings"
 )
 
@@ -39,65 +41,149 @@ var commands = map[string]CommandEntry{
 		name:  "metar",
 		cmd:   MetarCmd,
 		desc:  "Fetch METARs for station(s)",
-		usage: "STATION1 [STATION2...]",
-		eg:    []string{"KBDU KDEN"},
+		usage: "STATION1 [STATION2...] [--decode]",
+		eg:    []string{"KBDU KDEN", "KBDU --decode"},
 	},
 	"metar-history": CommandEntry{
 		name:  "metar-history",
 		cmd:   MetarHistoryCmd,
 		desc:  "Fetch historical METARs for station(s)",
-		usage: "HOURS STATION1 [STATION2...]",
+		usage: "HOURS STATION1 [STATION2...] [--decode]",
 		eg:    []string{"7 KBDU", "3 KBDU KBJC"},
 	},
 	"metar-radius": CommandEntry{
 		name:  "metar-radius",
 		cmd:   MetarRadiusCmd,
 		desc:  "Fetch current METARs within radius of a location",
-		usage: "STATION|LAT,LON RADIUS",
+		usage: "STATION|LAT,LON RADIUS [--decode]",
 		eg:    []string{"KBDU 50", "-105.23,40.03 50", "KBDU+10E"},
 	},
+	"taf": CommandEntry{
+		name:  "taf",
+		cmd:   TafCmd,
+		desc:  "Fetch TAFs for station(s)",
+		usage: "STATION1 [STATION2...] [--decode]",
+		eg:    []string{"KBJC", "KBJC KDEN --decode"},
+	},
+	"taf-radius": CommandEntry{
+		name:  "taf-radius",
+		cmd:   TafRadiusCmd,
+		desc:  "Fetch current TAFs within radius of a location",
+		usage: "STATION|LAT,LON RADIUS [--decode]",
+		eg:    []string{"KBDU 50", "40.03,-105.23 50"},
+	},
+	"pirep": CommandEntry{
+		name:  "pirep",
+		cmd:   PirepCmd,
+		desc:  "Turbulence and icing pilot reports within radius of a location",
+		usage: "STATION|LAT,LON RADIUS [HOURS] [--all]",
+		eg:    []string{"KBDU 150", "KBDU+20E 100 6", "40.03,-105.23 150 --all"},
+	},
 	"wind-course": CommandEntry{
 		name:  "wind-course",
 		cmd:   WindCorrectionCmd,
 		desc:  "Wind correction course calculation",
-		usage: "TAS COURSE WIND_SPEED@WIND_DIRECTION [DISTANCE]",
-		eg:    []string{"118 310 12@270", "118 310 12@270 23"},
+		usage: "TAS COURSE[T|M] WIND_SPEED@WIND_DIRECTION[T|M] [DISTANCE] [--at POSITION]",
+		eg:    []string{"118 310 12@270", "118 310 12@270 23", "118 310M 12@270 --at KBDU"},
 	},
 	"wind-route": CommandEntry{
 		name:  "wind-route",
 		cmd:   WindCorrectionRouteCmd,
-		desc:  "Wind correction calculation for a route between two locations",
-		usage: "TAS WIND_SPEED@WIND_DIRECTION ORIGIN DEST",
-		eg:    []string{"118 12@270 KBDU KCYS", "118 12@270 KBDU+5E -117.65,41.51"},
+		desc:  "Wind correction calculation for a route between two locations, or along a route string, with covering VORs at --alt",
+		usage: "TAS WIND_SPEED@WIND_DIRECTION[T|M]|auto ORIGIN [VIA...] DEST [--alt ALTITUDE] [--rhumb]",
+		eg:    []string{"118 12@270 KBDU KCYS", "118 12@270 KBDU+5E -117.65,41.51", "118 auto KBDU KCYS --alt 9500", "118 12@270 KBDU PANC --rhumb", "118 auto KBDU DVV V8 AKO KCYS --alt 9500"},
+	},
+	"gc-points": CommandEntry{
+		name:  "gc-points",
+		cmd:   GreatCirclePointsCmd,
+		desc:  "Evenly spaced checkpoints along the great circle between two locations",
+		usage: "ORIGIN DEST [--every NM] [--snap NM]",
+		eg:    []string{"KBDU KMCI --every 25", "KBDU KMCI --every 50 --snap 5"},
+	},
+	"offcourse": CommandEntry{
+		name:  "offcourse",
+		cmd:   OffCourseCmd,
+		desc:  "Position relative to the course between two locations",
+		usage: "ORIGIN DEST POSITION [--within NM]",
+		eg:    []string{"KBDU KCOS KBDU+20S+3W", "KBDU KCYS 40.6,-104.9 --within 5"},
 	},
 	"coord": CommandEntry{
 		name:  "coord",
 		cmd:   CoordCmd,
 		desc:  "Coordinate of a location as a latitude,longitude pair",
-		usage: "STATION",
-		eg:    []string{"KBDU", "KBDU+8S+23E", "KBDU+7@320", "KBDU+23E+7@320"},
+		usage: "POSITION [--format decimal|dms|ddm|nasr|utm|mgrs|all]",
+		eg:    []string{"KBDU", "KBDU+8S+23E", "KBDU+7@320", "KBDU+23E+7@320", "KBDU --format mgrs", "\"N40 02.35 W105 13.57\" --format all"},
 	},
 	"dist": CommandEntry{
 		name:  "dist",
 		cmd:   DistCmd,
-		desc:  "Distance between two locations",
-		usage: "STATION|LAT,LON STATION|LAT,LON",
-		eg:    []string{"KBDU KCOS", "-105.23,40.03 -117.65,41.51", "-105.23,40.03 KBDU+50W"},
+		desc:  "Distance between two locations, or along a route string",
+		usage: "STATION|LAT,LON [VIA...] STATION|LAT,LON [--rhumb]",
+		eg:    []string{"KBDU KCOS", "-105.23,40.03 -117.65,41.51", "-105.23,40.03 KBDU+50W", "KBDU PANC --rhumb", "KBDU DVV V8 AKO KCYS", "\"KBDU BJC+5N KCYS\""},
 	},
 	"apt": CommandEntry{
 		name:  "apt",
 		cmd:   AptCmd,
-		desc:  "Airport basic geographical data",
-		usage: "AIRPORT",
-		eg:    []string{"KBDU"},
+		desc:  "Airport or other landing facility summary with runways",
+		usage: "AIRPORT [--type TYPE[,TYPE...]]",
+		eg:    []string{"KBDU", "ANC", "CO12 --type heliport"},
 	},
-	/* "leg": CommandEntry{
+	"nearest": CommandEntry{
+		name:  "nearest",
+		cmd:   NearestCmd,
+		desc:  "Landing facilities closest to a location",
+		usage: "POSITION [COUNT] [--type airport|balloonport|seaplane|gliderport|heliport|ultralight[,...]]",
+		eg:    []string{"KBDU", "KBDU+20E 10", "40.0,-105.0 3 --type glider,seaplane"},
+	},
+	"navaid": CommandEntry{
+		name:  "navaid",
+		cmd:   NavaidCmd,
+		desc:  "Navaid frequency, ident and service volume, or VORs covering a leg",
+		usage: "ID | ORIGIN DEST --alt ALTITUDE",
+		eg:    []string{"BJC", "KBDU KCYS --alt 9500"},
+	},
+	"leg": CommandEntry{
 		name:  "leg",
 		cmd:   CreateLegCmd,
-		desc:  "Create a leg of a flight path",
-		usage: "ORIGIN DEST",
-		eg:    []string{"KBDU KCOS"},
-	},*/
+		desc:  "Create a flight plan (.flgt) for a leg, written to stdout",
+		usage: "ORIGIN[:FPM:RPM] DEST [--waypoints POS:ALT:FPM:RPM...]",
+		eg:    []string{"KBDU KCOS > myflight.flgt", "KBDU:700:2500 KCOS --waypoints BJC:9000:500:2400 KBDU+30S:9500:0:2400"},
+	},
+	"leg-wx": CommandEntry{
+		name:  "leg-wx",
+		cmd:   LegWxCmd,
+		desc:  "Annotate a flight plan with the weather expected at each waypoint",
+		usage: "[PLAN] [--tas KTS] [--depart TIME] [--auto]",
+		eg:    []string{"myflight.flgt > myflight.flgtwx", "--tas 110 --depart 1830Z --auto < myflight.flgt"},
+	},
+	"aircraft": CommandEntry{
+		name:  "aircraft",
+		cmd:   AircraftCmd,
+		desc:  "Aircraft performance, or the profile as JSON if no altitude is given",
+		usage: "AIRCRAFT [ALTITUDE [ISA_DEVIATION [RPM]]]",
+		eg:    []string{"C172 7500 +10 2400", "C172 > ~/.flight/aircraft/C172.json"},
+	},
+	"fuel": CommandEntry{
+		name:  "fuel",
+		cmd:   FuelCmd,
+		desc:  "Fuel required for a flight plan, with reserves and fuel stops",
+		usage: "AIRCRAFT [PLAN] [--reserve day|night|ifr] [--alternate STATION[:ALT]] [--fuel GAL] [--rpm RPM]",
+		eg:    []string{"C172 < myflight.flgtwx", "C172 myflight.flgtwx --reserve night --alternate KAPA --fuel 40"},
+	},
+	"weight-balance": CommandEntry{
+		name:  "weight-balance",
+		cmd:   WeightBalanceCmd,
+		desc:  "Takeoff and landing weight and balance against the CG envelopes",
+		usage: "AIRCRAFT [STATION=LOAD...] [--plan PLAN|--burn GAL]",
+		eg:    []string{"C172", "C172 front=340 rear=0 baggage1=40 baggage2=0 fuel=40 --plan myflight.flgtwx"},
+	},
+	"leg-calc": CommandEntry{
+		name:  "leg-calc",
+		cmd:   LegCalcCmd,
+		desc:  "Navigation log for a weather annotated flight plan",
+		usage: "AIRCRAFT [RPM] [PLAN] [--export]",
+		eg:    []string{"C172 < myflight.flgtwx", "C172 2500 myflight.flgtwx", "C172 myflight.flgtwx --export > myflight-toc.flgtwx"},
+	},
 }
 
 func (cmd CommandEntry) getUsageError() error {
@@ -110,7 +196,17 @@ func (cmd CommandEntry) getUsageError() error {
 	return errors.New(strings.Join(msg, "\n"))
 }
 
+// Selects the earth model used for distances and courses, sphere or wgs84
+const earthModelEnv = "FLIGHT_EARTH_MODEL"
+
 func Exec(cmdName string, argv []string) error {
+	if m, exists := os.LookupEnv(earthModelEnv); exists {
+		if model, err := geo.ParseModel(m); err != nil {
+			return err
+		} else {
+			geo.DefaultModel = model
+		}
+	}
 	if cmdName == helpCmdName || cmdName == "" {
 		// Help
 		return help(commands, argv)
@@ -135,6 +231,14 @@ func help(commands map[string]CommandEntry, argv []string) error {
 		fmt.Println(" e.g.,  flight help metar-radius")
 		fmt.Println("        flight dist kbdu+5W 40.0,-105.2")
 		fmt.Println("")
+		fmt.Printf("Distances and courses use the WGS-84 ellipsoid, set %s=sphere\n", earthModelEnv)
+		fmt.Println("for the spherical model.")
+		fmt.Println("Directions are true unless suffixed M for magnetic, e.g., 310M. Magnetic")
+		fmt.Println("variation is from the World Magnetic Model coefficients in WMM.COF, or")
+		fmt.Println("published airport variation.")
+		fmt.Println("Route strings join positions by airways entered and exited at fixes on")
+		fmt.Println("them, e.g., KBDU DVV V8 AKO KCYS, which are expanded using AWY.txt.")
+		fmt.Println("")
 		fmt.Println("Commands:")
 		// Get length of the longest command
 		max := 0
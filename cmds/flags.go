@@ -0,0 +1,38 @@
+package cmds
+
+import (
+	"errors"
+)
+
+// Removes a boolean flag from argv, reporting whether it was present
+func popFlag(argv []string, name string) (bool, []string) {
+	found := false
+	rest := []string{}
+	for _, a := range argv {
+		if a == name {
+			found = true
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	return found, rest
+}
+
+// Removes a flag and its value from argv, e.g., --every 25
+// The value is nil if the flag was not present.
+func popFlagValue(argv []string, name string) (*string, []string, error) {
+	var value *string
+	rest := []string{}
+	for i := 0; i < len(argv); i++ {
+		if argv[i] != name {
+			rest = append(rest, argv[i])
+		} else if i+1 >= len(argv) {
+			return nil, argv, errors.New("Missing value for flag " + name)
+		} else {
+			v := argv[i+1]
+			value = &v
+			i++
+		}
+	}
+	return value, rest, nil
+}
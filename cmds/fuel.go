@@ -0,0 +1,185 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/aircraft"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/plan"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var reserveRules = map[string]time.Duration{
+	"day":   plan.ReserveVfrDay,
+	"night": plan.ReserveVfrNight,
+	"ifr":   plan.ReserveIfr,
+}
+
+func FuelCmd(cmd CommandEntry, argv []string) error {
+	rulesFlag, argv, err := popFlagValue(argv, "--reserve")
+	if err != nil {
+		return err
+	}
+	altFlag, argv, err := popFlagValue(argv, "--alternate")
+	if err != nil {
+		return err
+	}
+	fuelFlag, argv, err := popFlagValue(argv, "--fuel")
+	if err != nil {
+		return err
+	}
+	rpmFlag, argv, err := popFlagValue(argv, "--rpm")
+	if err != nil {
+		return err
+	}
+	if len(argv) < 1 || len(argv) > 2 {
+		return cmd.getUsageError()
+	}
+	ac, err := aircraft.Load(argv[0])
+	if err != nil {
+		return err
+	}
+	var path *string
+	if len(argv) == 2 {
+		path = &argv[1]
+	}
+
+	rules := "day"
+	if rulesFlag != nil {
+		rules = strings.ToLower(*rulesFlag)
+	}
+	reserve, exists := reserveRules[rules]
+	if !exists {
+		return errors.New("Invalid reserve, expected day, night or ifr: " + rules)
+	}
+	onBoard := ac.UsableGal
+	if fuelFlag != nil {
+		if onBoard, err = strconv.ParseFloat(*fuelFlag, 64); err != nil || onBoard < 0 {
+			return errors.New("Invalid fuel, must be a non-negative number of gallons: " + *fuelFlag)
+		}
+	}
+	rpm := 0
+	if rpmFlag != nil {
+		if rpm, err = strconv.Atoi(*rpmFlag); err != nil || rpm <= 0 {
+			return errors.New("Invalid rpm, must be a positive integer: " + *rpmFlag)
+		}
+	}
+
+	p, err := loadPlan(path)
+	if err != nil {
+		return err
+	}
+	log, err := p.NavLog(ac, rpm)
+	if err != nil {
+		return err
+	}
+	// Apts are needed for the alternate and for fuel stops, so only loaded on demand
+	var apts *data.Apts
+	loadApts := func() (data.Apts, error) {
+		if apts == nil {
+			a, err := data.LoadApts()
+			if err != nil {
+				return data.Apts{}, err
+			}
+			apts = &a
+		}
+		return *apts, nil
+	}
+	var altLog *plan.NavLog
+	altName := ""
+	if altFlag != nil {
+		a, err := loadApts()
+		if err != nil {
+			return err
+		}
+		l, err := alternateNavLog(a, p, *altFlag, ac, rpm)
+		if err != nil {
+			return err
+		}
+		altLog = &l
+		altName = " " + l.Legs[len(l.Legs)-1].To.Name
+	}
+
+	f := log.FuelPlan(onBoard, reserve, altLog)
+	fmt.Printf("%s (%s), %.1f gal usable, %.1f gal on board\n\n", ac.Name, ac.Id, ac.UsableGal, onBoard)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LEG\tDIST\tTIME\tCLIMB\tCRUISE\tDESCENT\tFUEL\tREMAINING")
+	for _, l := range f.Legs {
+		fmt.Fprintf(w, "%s - %s\t%.1f\t%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n",
+			l.Leg.From.Name, l.Leg.To.Name, l.Leg.Leg.Dist, formatEte(l.Leg.Leg.Ete),
+			l.Leg.Climb.Fuel, l.Leg.Cruise.Fuel, l.Leg.Descent.Fuel, l.Leg.Leg.Fuel, l.Remaining)
+	}
+	w.Flush()
+	fmt.Println("")
+	fmt.Printf("%-24s %5.1f gal\n", "Taxi", f.Taxi)
+	fmt.Printf("%-24s %5.1f gal\n", "Climb", f.Climb)
+	fmt.Printf("%-24s %5.1f gal\n", "Cruise", f.Cruise)
+	fmt.Printf("%-24s %5.1f gal\n", "Descent", f.Descent)
+	fmt.Printf("%-24s %5.1f gal\n", "Alternate"+altName, f.Alternate)
+	fmt.Printf("%-24s %5.1f gal\n", fmt.Sprintf("Reserve (%s, %.0f min)", rules, reserve.Minutes()), f.Reserve)
+	fmt.Printf("%-24s %5.1f gal\n", "Required", f.Required)
+	fmt.Printf("%-24s %5.1f gal\n", "Extra", f.OnBoard-f.Required)
+	for _, l := range f.Legs {
+		for _, warning := range l.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+	if f.Required <= f.OnBoard {
+		return nil
+	}
+
+	fmt.Printf("\nTrip exceeds the fuel on board by %.1f gal, suggested fuel stops:\n", f.Required-f.OnBoard)
+	a, err := loadApts()
+	if err != nil {
+		return err
+	}
+	all, err := a.All()
+	if err != nil {
+		return err
+	}
+	airports := []data.Apt{}
+	for _, apt := range all {
+		if apt.Type == data.Airport {
+			airports = append(airports, apt)
+		}
+	}
+	stops, err := log.FuelStops(f, airports)
+	for _, s := range stops {
+		fmt.Printf("  %-6s %4.0f NM along route, %2.0f NM off track, ~%.1f gal remaining\n",
+			s.Apt.Id, s.AlongNM, s.OffTrackNM, s.ArrivalFuel)
+	}
+	return err
+}
+
+// The alternate is flown direct from the destination at the altitude of the
+// final leg, unless given as STATION:ALT
+func alternateNavLog(apts data.Apts, p plan.Plan, spec string, ac aircraft.Profile, rpm int) (plan.NavLog, error) {
+	id, alt, err := splitSpec(spec, 1)
+	if err != nil {
+		return plan.NavLog{}, err
+	}
+	dest, err := CreateAptWaypoint(apts, plan.Dest, id)
+	if err != nil {
+		return plan.NavLog{}, err
+	}
+	origin := p.Dest()
+	origin.Kind = plan.Origin
+	cruise := plan.Waypoint{
+		Kind: plan.Enroute,
+		Name: "ALTERNATE",
+		Pos:  geo.IntermediatePoint(origin.Pos, dest.Pos, 0.5),
+		Alt:  p.LegAlt(p.Legs() - 1),
+		Rpm:  p.Waypoints[p.Legs()-1].Rpm,
+		Wx:   origin.Wx,
+	}
+	if alt != nil {
+		cruise.Alt = alt[0]
+	}
+	altPlan := plan.Plan{Waypoints: []plan.Waypoint{origin, cruise, dest}}
+	return altPlan.NavLog(ac, rpm)
+}
@@ -0,0 +1,125 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Spacing of the points, unless given by --every
+const default_gc_spacing_nm = 50
+
+// NATFIX types points may be snapped to, navaids and reporting points
+var snapFixTypes = map[string]bool{
+	"VOR":     true,
+	"VOR/DME": true,
+	"VORTAC":  true,
+	"TACAN":   true,
+	"DME":     true,
+	"NDB":     true,
+	"NDB/DME": true,
+	"REP-PT":  true,
+}
+
+func GreatCirclePointsCmd(cmd CommandEntry, argv []string) error {
+	everyFlag, argv, err := popFlagValue(argv, "--every")
+	if err != nil {
+		return err
+	}
+	snapFlag, argv, err := popFlagValue(argv, "--snap")
+	if err != nil {
+		return err
+	}
+	if len(argv) != 2 {
+		return cmd.getUsageError()
+	}
+	every := float64(default_gc_spacing_nm)
+	if everyFlag != nil {
+		if every, err = strconv.ParseFloat(*everyFlag, 64); err != nil || every <= 0 {
+			return errors.New("Invalid spacing, must be a positive number of NM: " + *everyFlag)
+		}
+	}
+	snap := 0.0
+	if snapFlag != nil {
+		if snap, err = strconv.ParseFloat(*snapFlag, 64); err != nil || snap <= 0 {
+			return errors.New("Invalid snap tolerance, must be a positive number of NM: " + *snapFlag)
+		}
+	}
+
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	origin, err := parse.ParsePos(natfix, argv[0])
+	if err != nil {
+		return err
+	}
+	dest, err := parse.ParsePos(natfix, argv[1])
+	if err != nil {
+		return err
+	}
+	if origin == dest {
+		return errors.New("Origin and destination are the same location")
+	}
+	points, err := geo.GreatCirclePoints(origin, dest, every)
+	if err != nil {
+		return err
+	}
+
+	names, offsets := make([]string, len(points)), make([]float64, len(points))
+	if snap != 0 {
+		names, offsets = snapPoints(natfix, points, snap, argv[0], argv[1])
+	}
+	names[0], names[len(names)-1] = argv[0], argv[1]
+	checkpoints, err := geo.Checkpoints(points)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POS\tLAT,LON\tTC\tDIST\tTOT DIST\tSNAP\t")
+	total := 0.0
+	for i, c := range checkpoints {
+		tc, dist, off := "-", "-", ""
+		if !math.IsNaN(c.Course) {
+			tc, dist = fmt.Sprintf("%03.0f", c.Course), fmt.Sprintf("%.1f", c.DistNM)
+		}
+		if names[i] != "" && i != 0 && i != len(checkpoints)-1 {
+			off = fmt.Sprintf("%.1f", offsets[i])
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t%s\t\n", names[i], c.Pos, tc, dist, total, off)
+		total += c.DistNM
+	}
+	return w.Flush()
+}
+
+// Replaces intermediate points, in place, with navaids or reporting points
+// within snap NM, keeping them in order along the route and skipping any
+// already used. Returns the fix names and how far each point moved.
+func snapPoints(natfix data.Natfix, points []geo.Coord, snap float64, origin, dest string) ([]string, []float64) {
+	names := make([]string, len(points))
+	offsets := make([]float64, len(points))
+	start, end := points[0], points[len(points)-1]
+	used := map[string]bool{strings.ToUpper(origin): true, strings.ToUpper(dest): true}
+	for i := 1; i+1 < len(points); i++ {
+		prev := geo.AlongTrackNM(start, end, points[i-1])
+		next := geo.AlongTrackNM(start, end, points[i+1])
+		accept := func(id string, pos geo.Coord) bool {
+			along := geo.AlongTrackNM(start, end, pos)
+			return snapFixTypes[natfix.StationType(id)] && !used[id] && along > prev && along < next
+		}
+		if id, pos, err := natfix.NearestWhere(points[i], snap, accept); err == nil {
+			used[id] = true
+			names[i], offsets[i] = id, geo.GlobeDistNM(points[i], pos)
+			points[i] = pos
+		}
+	}
+	return names, offsets
+}
@@ -0,0 +1,78 @@
+package cmds
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"strings"
+	"testing"
+)
+
+func natfixLine(id, lat, lon, stationType string) string {
+	return fmt.Sprintf("I %-5s %s %s 'ZDV  CO    %-7s", id, lat, lon, stationType)
+}
+
+// A 48 NM route due north along 105W, so every point shares a meridian,
+// with checkpoints every 12 NM at 40.2N, 40.4N and 40.6N
+func TestSnapPointsNorthSouth(t *testing.T) {
+	lines := []string{
+		natfixLine("AAAA", "400000N", "1050000W", "ARPT"),
+		natfixLine("BBBB", "404800N", "1050000W", "ARPT"),
+		// Nearest the first checkpoint, but past the midpoint to the second
+		natfixLine("AHEAD", "402024N", "1050000W", "REP-PT"),
+		// Only in range of the second checkpoint, and behind AHEAD
+		natfixLine("BEHND", "401948N", "1050910W", "REP-PT"),
+		// On the third checkpoint, but not a navaid or reporting point
+		natfixLine("RNAV", "403600N", "1050000W", "RNAV-WP"),
+		natfixLine("VOR", "403712N", "1050112W", "VORTAC"),
+		natfixLine("BAD", "4036X0N", "1050000W", "REP-PT"),
+	}
+	natfix, err := data.ParseNatfix(strings.NewReader("NATFIX\n'20261018\n" + strings.Join(lines, "\n") + "\n$\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin, _ := natfix.GetFix("AAAA")
+	dest, _ := natfix.GetFix("BBBB")
+	points, err := geo.GreatCirclePoints(origin, dest, 12.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 {
+		t.Fatalf("Got %d points, want 5", len(points))
+	}
+	names, offsets := snapPoints(natfix, points, 9, "AAAA", "BBBB")
+	want := []string{"", "AHEAD", "", "VOR", ""}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Point %d: got %q, want %q", i, names[i], want[i])
+		}
+	}
+	if ahead, _ := natfix.GetFix("AHEAD"); points[1] != ahead {
+		t.Errorf("Point 1: got %s, want AHEAD at %s", points[1], ahead)
+	}
+	if offsets[1] < 8 || offsets[1] > 9 || offsets[3] > 2 {
+		t.Errorf("Offsets: got %v", offsets)
+	}
+	// Unsnapped points stay on the route
+	if points[2].Lon() != -105 || points[2].Lat() < 40.39 || points[2].Lat() > 40.41 {
+		t.Errorf("Point 2: got %s", points[2])
+	}
+}
+
+func TestSnapPointsExcludesEnds(t *testing.T) {
+	lines := []string{
+		natfixLine("ORIG", "400000N", "1050000W", "VORTAC"),
+		natfixLine("DEST", "401000N", "1050000W", "VORTAC"),
+	}
+	natfix, err := data.ParseNatfix(strings.NewReader("NATFIX\n'20261018\n" + strings.Join(lines, "\n") + "\n$\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin, _ := natfix.GetFix("ORIG")
+	dest, _ := natfix.GetFix("DEST")
+	points, _ := geo.GreatCirclePoints(origin, dest, 5)
+	// Both ends are within range of the midpoint
+	if names, _ := snapPoints(natfix, points, 10, "orig", "dest"); names[1] != "" {
+		t.Errorf("Midpoint: got %q, want it left unsnapped", names[1])
+	}
+}
@@ -1,130 +1,213 @@
 package cmds
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"github.com/cragcraig/flight/data"
-	"github.com/cragcraig/flight/geo"
 	"github.com/cragcraig/flight/parse"
+	"github.com/cragcraig/flight/plan"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 )
 
-type WaypointAndEnergy struct {
-	point Waypoint
-	fpm   int
-	rpm   int
-}
-
-func (v WaypointAndEnergy) String() string {
-	return fmt.Sprintf("{%s %dfpm %drpm}", v.point, v.fpm, v.rpm)
-}
-
-type Waypoint struct {
-	pos      geo.Coord
-	alt      int
-	opt_desc *string
-}
-
-func (w Waypoint) String() string {
-	var pos string
-	if w.opt_desc != nil {
-		pos = *w.opt_desc
-	} else {
-		pos = w.pos.String()
-	}
-	return fmt.Sprintf("{%s %dft}", pos, w.alt)
-}
+const waypointsFlag = "--waypoints"
 
-func CreateAptWaypoint(apts data.Apts, airport string) (Waypoint, error) {
+func CreateAptWaypoint(apts data.Apts, kind, airport string) (plan.Waypoint, error) {
 	apt, err := apts.GetApt(airport)
 	if err != nil {
-		return Waypoint{
-			pos: geo.ErrCoord(),
-		}, err
-	}
-	return Waypoint{
-		pos:      apt.Coord,
-		alt:      apt.Alt,
-		opt_desc: &airport,
+		return plan.Waypoint{}, err
+	}
+	if apt.Type != data.Airport {
+		warnf("Warning: %s is a %s", apt.Id, apt.Type)
+	}
+	return plan.Waypoint{
+		Kind: kind,
+		Name: apt.Id,
+		Pos:  apt.Coord,
+		Alt:  apt.Alt,
 	}, nil
 }
 
-func ParseWaypoint(natfix data.Natfix, posDesc string, alt int) (Waypoint, error) {
+func ParseWaypoint(natfix data.Natfix, posDesc string, alt int) (plan.Waypoint, error) {
 	if pos, err := parse.ParsePos(natfix, posDesc); err != nil {
-		return Waypoint{}, err
+		return plan.Waypoint{}, err
 	} else {
-		return Waypoint{
-			pos:      pos,
-			alt:      alt,
-			opt_desc: &posDesc,
+		return plan.Waypoint{
+			Kind: plan.Enroute,
+			Name: posDesc,
+			Pos:  pos,
+			Alt:  alt,
 		}, nil
 	}
 }
 
+// Reads a flight plan from the file at path, or from stdin if path is nil
+func loadPlan(path *string) (plan.Plan, error) {
+	if path == nil {
+		return plan.Read(os.Stdin)
+	}
+	file, err := os.Open(*path)
+	if err != nil {
+		return plan.Plan{}, errors.New("Unable to load flight plan: " + err.Error())
+	}
+	defer file.Close()
+	return plan.Read(file)
+}
+
 func CreateLegCmd(cmd CommandEntry, argv []string) error {
-	if len(argv) < 2 {
+	// Everything following --waypoints is a waypoint
+	var specs []string
+	interactive := true
+	for i, a := range argv {
+		if a == waypointsFlag {
+			specs = argv[i+1:]
+			argv = argv[:i]
+			interactive = false
+			break
+		}
+	}
+	if len(argv) != 2 {
 		return cmd.getUsageError()
 	}
 
-	if natfix, err := data.LoadNatfix(); err != nil {
+	natfix, err := data.LoadNatfix()
+	if err != nil {
 		return err
-	} else if apts, err := data.LoadApts(); err != nil {
+	}
+	apts, err := data.LoadApts()
+	if err != nil {
 		return err
-	} else {
-		origin, err := CreateAptWaypoint(apts, argv[0])
-		if err != nil {
-			return err
-		}
-		dest, err := CreateAptWaypoint(apts, argv[1])
-		if err != nil {
-			return err
-		}
-		leg := []WaypointAndEnergy{}
-		// origin
-		if v, err := promptAptEnergy(origin); err != nil {
-			return err
-		} else {
-			leg = append(leg, v)
-		}
-		// Waypoints
-		for i := 1; true; i++ {
-			if v, err := promptWaypointAndEnergy(natfix, i); err != nil {
+	}
+	originId, originEnergy, err := splitSpec(argv[0], 2)
+	if err != nil {
+		return err
+	}
+	origin, err := CreateAptWaypoint(apts, plan.Origin, originId)
+	if err != nil {
+		return err
+	}
+	dest, err := CreateAptWaypoint(apts, plan.Dest, argv[1])
+	if err != nil {
+		return err
+	}
+
+	// Prompts go to stderr so the plan can be redirected to a file
+	in := bufio.NewReader(os.Stdin)
+	if originEnergy != nil {
+		origin.Fpm, origin.Rpm = originEnergy[0], originEnergy[1]
+	} else if !interactive {
+		return errors.New("Non-interactive mode requires origin climb and power, e.g., KBDU:700:2500")
+	} else if origin, err = promptAptEnergy(in, origin); err != nil {
+		return err
+	}
+	p := plan.Plan{Waypoints: []plan.Waypoint{origin}}
+
+	if interactive {
+		for i := 2; true; i++ {
+			if w, err := promptWaypointAndEnergy(in, natfix, i); err == io.EOF {
 				break
+			} else if err != nil {
+				return err
+			} else {
+				p.Waypoints = append(p.Waypoints, w)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Arrive %s\n", dest.Name)
+	} else {
+		for _, s := range specs {
+			if w, err := parseWaypointSpec(natfix, s); err != nil {
+				return err
 			} else {
-				leg = append(leg, v)
+				p.Waypoints = append(p.Waypoints, w)
 			}
 		}
-		// destination
-		leg = append(leg, WaypointAndEnergy{
-			point: dest,
-			fpm:   0,
-			rpm:   0,
-		})
-		fmt.Printf("%v\n", leg)
-	}
-	return nil
+	}
+	p.Waypoints = append(p.Waypoints, dest)
+	return p.Write(os.Stdout)
 }
 
-func promptAptEnergy(apt Waypoint) (WaypointAndEnergy, error) {
-	if apt.opt_desc == nil {
-		panic("unexpected non-airport checkpoint")
-	}
-	fmt.Printf("%s: fpm rpm > ", *apt.opt_desc)
-	var rpm, fpm int
-	if _, err := fmt.Scanf("%d %d", &fpm, &rpm); err != nil {
-		return WaypointAndEnergy{}, err
-	} else {
-		return WaypointAndEnergy{apt, fpm, rpm}, nil
+// Splits a trailing :N:N... suffix of n integers from a spec, if present
+// e.g., KBDU:700:2500
+func splitSpec(spec string, n int) (string, []int, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) == 1 {
+		return spec, nil, nil
+	} else if len(parts) != n+1 {
+		return "", nil, fmt.Errorf("Expected %d values after the position in %s", n, spec)
 	}
+	values := make([]int, n)
+	for i, p := range parts[1:] {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return "", nil, errors.New("Invalid integer in " + spec + ": " + p)
+		}
+		values[i] = v
+	}
+	return parts[0], values, nil
 }
 
-func promptWaypointAndEnergy(natfix data.Natfix, n int) (WaypointAndEnergy, error) {
-	fmt.Printf("#%d: pos alt fpm rpm > ", n)
-	var pos string
-	var alt, rpm, fpm int
-	if _, err := fmt.Scanf("%s %d %d %d", &pos, &alt, &fpm, &rpm); err != nil {
-		return WaypointAndEnergy{}, err
-	} else if w, err := ParseWaypoint(natfix, pos, alt); err != nil {
-		return WaypointAndEnergy{}, err
-	} else {
-		return WaypointAndEnergy{w, fpm, rpm}, nil
+// POS:ALT:FPM:RPM, e.g., BJC:9000:500:2400
+func parseWaypointSpec(natfix data.Natfix, spec string) (plan.Waypoint, error) {
+	pos, values, err := splitSpec(spec, 3)
+	if err != nil {
+		return plan.Waypoint{}, err
+	} else if values == nil {
+		return plan.Waypoint{}, errors.New("Waypoint must be of the form POS:ALT:FPM:RPM: " + spec)
+	}
+	w, err := ParseWaypoint(natfix, pos, values[0])
+	if err != nil {
+		return plan.Waypoint{}, err
+	}
+	w.Fpm, w.Rpm = values[1], values[2]
+	return w, nil
+}
+
+func promptLine(in *bufio.Reader, prompt string) ([]string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	l, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || len(l) == 0) {
+		return nil, io.EOF
+	}
+	return strings.Fields(l), nil
+}
+
+func promptAptEnergy(in *bufio.Reader, apt plan.Waypoint) (plan.Waypoint, error) {
+	fields, err := promptLine(in, fmt.Sprintf("1 %s (fpm, rpm) > ", apt.Name))
+	if err != nil {
+		return plan.Waypoint{}, errors.New("Origin climb rate and power are required")
+	} else if len(fields) != 2 {
+		return plan.Waypoint{}, errors.New("Expected: fpm rpm")
+	}
+	if apt.Fpm, err = strconv.Atoi(fields[0]); err != nil {
+		return plan.Waypoint{}, errors.New("Invalid fpm: " + fields[0])
+	}
+	if apt.Rpm, err = strconv.Atoi(fields[1]); err != nil {
+		return plan.Waypoint{}, errors.New("Invalid rpm: " + fields[1])
+	}
+	return apt, nil
+}
+
+// Returns io.EOF once the user enters an empty line
+func promptWaypointAndEnergy(in *bufio.Reader, natfix data.Natfix, n int) (plan.Waypoint, error) {
+	fields, err := promptLine(in, fmt.Sprintf("%d (alt, pos, fpm, rpm) > ", n))
+	if err != nil || len(fields) == 0 {
+		return plan.Waypoint{}, io.EOF
+	} else if len(fields) != 4 {
+		return plan.Waypoint{}, errors.New("Expected: alt pos fpm rpm")
+	}
+	ints := [3]int{}
+	for i, f := range []string{fields[0], fields[2], fields[3]} {
+		if ints[i], err = strconv.Atoi(f); err != nil {
+			return plan.Waypoint{}, errors.New("Invalid integer: " + f)
+		}
+	}
+	w, err := ParseWaypoint(natfix, fields[1], ints[0])
+	if err != nil {
+		return plan.Waypoint{}, err
 	}
+	w.Fpm, w.Rpm = ints[1], ints[2]
+	return w, nil
 }
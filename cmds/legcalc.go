@@ -0,0 +1,142 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/aircraft"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/plan"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func LegCalcCmd(cmd CommandEntry, argv []string) error {
+	export, argv := popFlag(argv, "--export")
+	if len(argv) < 1 || len(argv) > 3 {
+		return cmd.getUsageError()
+	}
+	ac, err := aircraft.Load(argv[0])
+	if err != nil {
+		return err
+	}
+	// Optional power setting and plan file, in either order
+	rpm := 0
+	var path *string
+	for i := 1; i < len(argv); i++ {
+		if v, err := strconv.Atoi(argv[i]); err == nil && rpm == 0 {
+			if v <= 0 {
+				return errors.New("Invalid rpm, must be a positive integer: " + argv[i])
+			}
+			rpm = v
+		} else if path == nil {
+			path = &argv[i]
+		} else {
+			return cmd.getUsageError()
+		}
+	}
+
+	p, err := loadPlan(path)
+	if err != nil {
+		return err
+	}
+	log, err := p.NavLog(ac, rpm)
+	if err != nil {
+		return err
+	}
+	if export {
+		// The plan with computed top of climb and descent waypoints
+		return log.Plan().Write(os.Stdout)
+	}
+	printNavLog(log)
+	return nil
+}
+
+// One row per segment, so legs with a top of climb or descent span two rows
+func printNavLog(log plan.NavLog) {
+	fmt.Printf("%s (%s)\n\n", log.Aircraft.Name, log.Aircraft.Id)
+	// Compass headings only with a deviation card
+	card := log.Aircraft.DeviationCard
+	ch := ""
+	if len(card) != 0 {
+		ch = "CH\t"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "POS\tALT\tTC\tWCA\tTH\tMH\t%sTAS\tGS\tDIST\tTIME\tFUEL\tTOT DIST\tTOT TIME\tTOT FUEL\n", ch)
+	total := plan.Segment{}
+	for _, l := range log.Legs {
+		type row struct {
+			from plan.Waypoint
+			alt  int
+			s    plan.Segment
+		}
+		rows := []row{}
+		from := l.From
+		if l.Climb.Dist > 0 {
+			rows = append(rows, row{from, l.To.Alt, l.Climb})
+			if l.Toc != nil {
+				from = *l.Toc
+			}
+		}
+		if l.Cruise.Dist > 0 || len(rows) == 0 {
+			rows = append(rows, row{from, l.Alt, l.Cruise})
+		}
+		if l.Descent.Dist > 0 {
+			if l.Tod != nil {
+				from = *l.Tod
+			}
+			rows = append(rows, row{from, l.To.Alt, l.Descent})
+		}
+		for _, r := range rows {
+			total.Dist += r.s.Dist
+			total.Ete += r.s.Ete
+			total.Fuel += r.s.Fuel
+			if r.s.Dist < 0.05 && len(rows) > 1 {
+				// Negligible, e.g., cruise to a previously exported top of climb
+				continue
+			}
+			mh, compass := "-", ""
+			if l.From.Wx.Variation != nil {
+				m := geo.TrueToMagnetic(r.s.Heading, float64(*l.From.Wx.Variation))
+				mh = fmt.Sprintf("%03d", round(m)%360)
+				if len(card) != 0 {
+					compass = fmt.Sprintf("%03d\t", round(card.Compass(m))%360)
+				}
+			} else if len(card) != 0 {
+				compass = "-\t"
+			}
+			fmt.Fprintf(w, "%s\t%dft\t%03d\t%+d\t%03d\t%s\t%s%d\t%d\t%.1f\t%s\t%.1fgal\t%.1f\t%s\t%.1fgal\n",
+				r.from.Name,
+				r.alt,
+				round(l.Course)%360,
+				int(math.Round(geo.Wrap360(r.s.Heading-l.Course+180)-180)),
+				round(r.s.Heading)%360,
+				mh,
+				compass,
+				round(r.s.Tas),
+				round(r.s.Gs),
+				r.s.Dist,
+				formatEte(r.s.Ete),
+				r.s.Fuel,
+				total.Dist,
+				formatEte(total.Ete),
+				total.Fuel)
+		}
+	}
+	dest := log.Legs[len(log.Legs)-1].To
+	fmt.Fprintf(w, "%s\t%dft\n", dest.Name, dest.Alt)
+	w.Flush()
+	for _, l := range log.Legs {
+		for _, warning := range l.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+}
+
+// e.g., 1h05m
+func formatEte(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
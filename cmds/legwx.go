@@ -0,0 +1,368 @@
+package cmds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/metar"
+	"github.com/cragcraig/flight/parse"
+	"github.com/cragcraig/flight/plan"
+	"github.com/cragcraig/flight/taf"
+	"github.com/cragcraig/flight/winds"
+	"github.com/cragcraig/flight/wmm"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const default_tas = 100
+
+// Observations are preferred over forecasts for ETAs within this window
+const metar_window = time.Hour
+
+// Radii, in statute miles, searched when a station has no report of its own
+const metar_search_radius = 25
+const taf_search_radius = 50
+
+var etaOffsetRegexp = regexp.MustCompile(`^\+(\d{1,2})(\d{2})$`)
+
+func LegWxCmd(cmd CommandEntry, argv []string) error {
+	auto, argv := popFlag(argv, "--auto")
+	tasFlag, argv, err := popFlagValue(argv, "--tas")
+	if err != nil {
+		return err
+	}
+	departFlag, argv, err := popFlagValue(argv, "--depart")
+	if err != nil {
+		return err
+	}
+	if len(argv) > 1 {
+		return cmd.getUsageError()
+	}
+	var path *string
+	if len(argv) == 1 {
+		path = &argv[0]
+	}
+
+	tas := float64(default_tas)
+	if tasFlag != nil {
+		if tas, err = strconv.ParseFloat(*tasFlag, 64); err != nil || tas <= 0 {
+			return errors.New("Invalid TAS, must be a positive number: " + *tasFlag)
+		}
+	}
+	depart := time.Now().UTC()
+	if departFlag != nil {
+		if depart, err = parseDepartTime(*departFlag, depart); err != nil {
+			return err
+		}
+	}
+	p, err := loadPlan(path)
+	if err != nil {
+		return err
+	}
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	// Missing sources are reported but only leave their values unset
+	var model *wmm.Model
+	if m, err := wmm.Load(); err == nil {
+		model = &m
+	}
+	apts, err := data.LoadApts()
+	if err != nil && model == nil {
+		warnf("Magnetic variation unavailable: %s", err)
+	}
+	forecast, err := winds.Load(natfix)
+	if err != nil {
+		warnf("Winds aloft unavailable: %s", err)
+	}
+
+	// Prompt on the terminal when the plan itself is being read from stdin
+	var in *bufio.Reader
+	if !auto {
+		if path != nil {
+			in = bufio.NewReader(os.Stdin)
+		} else if tty, err := os.Open("/dev/tty"); err != nil {
+			return errors.New("Unable to prompt for overrides, try --auto: " + err.Error())
+		} else {
+			defer tty.Close()
+			in = bufio.NewReader(tty)
+		}
+	}
+
+	eta := depart
+	for i := range p.Waypoints {
+		w := &p.Waypoints[i]
+		if i > 0 {
+			eta = eta.Add(legDuration(p, i-1, tas))
+		}
+		if w.Wx.Eta == nil {
+			t := eta
+			w.Wx.Eta = &t
+		}
+		legAlt := 0
+		if i < p.Legs() {
+			legAlt = p.LegAlt(i)
+		} else {
+			legAlt = p.LegAlt(i - 1)
+		}
+		planned := w.Wx
+		annotateWaypoint(w, legAlt, model, apts, forecast)
+		printWaypointWx(i+1, *w, legAlt)
+		if in != nil {
+			annotated := w.Wx
+			if err := promptWxOverrides(in, i+1, w, depart); err != nil {
+				return err
+			}
+			if !w.Wx.Eta.Equal(*annotated.Eta) {
+				reannotateEta(w, planned, annotated)
+				annotateWaypoint(w, legAlt, model, apts, forecast)
+				printWaypointWx(i+1, *w, legAlt)
+			}
+		}
+		eta = *w.Wx.Eta
+	}
+	return p.Write(os.Stdout)
+}
+
+func warnf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+}
+
+// e.g., 1830Z (the next occurrence) or 2026-10-18T18:30:00Z
+func parseDepartTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("1504Z", strings.ToUpper(s))
+	if err != nil {
+		return time.Time{}, errors.New("Invalid departure time, e.g., 1830Z: " + s)
+	}
+	d := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if d.Before(now.Add(-time.Hour)) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d, nil
+}
+
+// Time en route for leg i, using the winds aloft at the start of the leg
+func legDuration(p plan.Plan, i int, tas float64) time.Duration {
+	from, to := p.Waypoints[i], p.Waypoints[i+1]
+	dist := geo.DistanceNM(from.Pos, to.Pos)
+	gs := tas
+	if course, err := geo.CourseCompass(from.Pos, to.Pos); err == nil && from.Wx.Wind != nil {
+		if _, v, err := geo.WindTriangle(geo.Compass2Rad(course), tas, *from.Wx.Wind); err == nil {
+			gs = v
+		}
+	}
+	return time.Duration(dist / gs * float64(time.Hour))
+}
+
+// Fills in any weather not already present in the plan. Magnetic variation
+// is from the World Magnetic Model if loaded, otherwise the nearest airport.
+func annotateWaypoint(w *plan.Waypoint, legAlt int, model *wmm.Model, apts data.Apts, forecast winds.Forecast) {
+	if w.Wx.Variation == nil && model != nil {
+		if v, err := model.Variation(w.Pos, float64(w.Alt), *w.Wx.Eta); err == nil {
+			rounded := int(math.Round(v))
+			w.Wx.Variation = &rounded
+		}
+	}
+	if w.Wx.Variation == nil {
+		if apt, err := aptForWaypoint(apts, *w); err == nil && apt.Variation != nil {
+			v := *apt.Variation
+			w.Wx.Variation = &v
+		}
+	}
+	if (w.Wx.Wind == nil || w.Wx.Temp == nil) && forecast.Stations != nil {
+		if aloft, err := forecast.At(w.Pos, legAlt); err != nil {
+			warnf("%s: %s", w.Name, err)
+		} else {
+			if w.Wx.Wind == nil {
+				wind := aloft.Wind
+				w.Wx.Wind = &wind
+			}
+			if w.Wx.Temp == nil && aloft.HasTemp {
+				temp := aloft.Temp
+				w.Wx.Temp = &temp
+			}
+		}
+	}
+	if (w.Kind != plan.Origin && w.Kind != plan.Dest) || len(w.Wx.Metar) != 0 || len(w.Wx.Taf) != 0 {
+		return
+	}
+	// Surface weather for departure and arrival
+	if w.Wx.Eta.Sub(time.Now()) < metar_window {
+		if m, err := nearestMetar(w.Name, w.Pos); err != nil {
+			warnf("%s: %s", w.Name, err)
+		} else {
+			w.Wx.Metar = m.RawText
+		}
+	} else if t, err := nearestTaf(w.Name, w.Pos); err != nil {
+		warnf("%s: %s", w.Name, err)
+	} else {
+		w.Wx.Taf = t.RawText
+	}
+}
+
+// Forgets the looked up weather that depends on the ETA, the surface weather and
+// variation, unless it came from the plan or an override
+func reannotateEta(w *plan.Waypoint, planned, annotated plan.Weather) {
+	if planned.Variation == nil && w.Wx.Variation == annotated.Variation {
+		w.Wx.Variation = nil
+	}
+	if len(planned.Metar) == 0 && len(planned.Taf) == 0 {
+		w.Wx.Metar, w.Wx.Taf = "", ""
+	}
+}
+
+// The airport at a waypoint, or the nearest airport for enroute waypoints
+func aptForWaypoint(apts data.Apts, w plan.Waypoint) (data.Apt, error) {
+	if w.Kind == plan.Origin || w.Kind == plan.Dest {
+		if apt, err := apts.GetApt(w.Name); err == nil {
+			return apt, nil
+		}
+	}
+	// Other facility types often don't publish a variation
+	if nearest, err := apts.NearestN(w.Pos, 1, []data.FacilityType{data.Airport}); err != nil {
+		return data.Apt{}, err
+	} else if len(nearest) == 0 {
+		return data.Apt{}, errors.New("No airports in the APT database")
+	} else {
+		return nearest[0], nil
+	}
+}
+
+func nearestMetar(station string, pos geo.Coord) (metar.Metar, error) {
+	if metars, err := metar.QueryStations([]string{station}, recency_upper_bound); err == nil && len(metars) != 0 {
+		return metars[0], nil
+	}
+	metars, err := metar.QueryRadius(pos, metar_search_radius, recency_upper_bound)
+	if err != nil {
+		return metar.Metar{}, err
+	} else if len(metars) == 0 {
+		return metar.Metar{}, errors.New("No METAR within " + strconv.Itoa(metar_search_radius) + " miles")
+	}
+	nearest := metars[0]
+	for _, m := range metars[1:] {
+		if geo.GlobeDistNM(pos, m.Coord()) < geo.GlobeDistNM(pos, nearest.Coord()) {
+			nearest = m
+		}
+	}
+	return nearest, nil
+}
+
+func nearestTaf(station string, pos geo.Coord) (taf.Taf, error) {
+	if tafs, err := taf.QueryStations([]string{station}, recency_upper_bound); err == nil && len(tafs) != 0 {
+		return tafs[0], nil
+	}
+	tafs, err := taf.QueryRadius(pos, taf_search_radius, recency_upper_bound)
+	if err != nil {
+		return taf.Taf{}, err
+	} else if len(tafs) == 0 {
+		return taf.Taf{}, errors.New("No TAF within " + strconv.Itoa(taf_search_radius) + " miles")
+	}
+	nearest := tafs[0]
+	for _, t := range tafs[1:] {
+		if geo.GlobeDistNM(pos, t.Coord()) < geo.GlobeDistNM(pos, nearest.Coord()) {
+			nearest = t
+		}
+	}
+	return nearest, nil
+}
+
+func printWaypointWx(n int, w plan.Waypoint, legAlt int) {
+	fmt.Fprintf(os.Stderr, "%d %dft, %s  ETA %s\n", n, w.Alt, w.Name, w.Wx.Eta.Format("1504Z"))
+	if len(w.Wx.Metar) != 0 {
+		fmt.Fprintf(os.Stderr, "  METAR: %s\n", w.Wx.Metar)
+	}
+	if len(w.Wx.Taf) != 0 {
+		fmt.Fprintf(os.Stderr, "  TAF: %s\n", w.Wx.Taf)
+		if f, err := taf.Parse(w.Wx.Taf, *w.Wx.Eta); err == nil {
+			if c, err := f.ConditionsAt(*w.Wx.Eta); err == nil {
+				fmt.Fprint(os.Stderr, "  ")
+				printTafConditionsTo(os.Stderr, "At ETA", c)
+			}
+		}
+	}
+	if w.Wx.Wind != nil {
+		temp := ""
+		if w.Wx.Temp != nil {
+			temp = fmt.Sprintf(", %.0f C", *w.Wx.Temp)
+		}
+		fmt.Fprintf(os.Stderr, "  %dft winds aloft: %d @ %d%s\n", legAlt,
+			round(w.Wx.Wind.Magnitude()), round(geo.Rad2Compass(w.Wx.Wind.AsAngle())), temp)
+	}
+	if w.Wx.Variation != nil {
+		fmt.Fprintf(os.Stderr, "  Mag Var: %d\n", *w.Wx.Variation)
+	}
+}
+
+// Accepts "auto" (or nothing) to keep the values shown, an ETA offset from
+// departure, e.g., +0130, or any of eta=+HHMM wind=SPEED@DIR[T|M] temp=C var=DEG
+func promptWxOverrides(in *bufio.Reader, n int, w *plan.Waypoint, depart time.Time) error {
+	fields, err := promptLine(in, fmt.Sprintf("%d %s > ", n, w.Name))
+	if err != nil {
+		return nil
+	}
+	// Converted to true once any variation override is known
+	var wind *geo.Vect
+	north := geo.TrueNorth
+	for _, f := range fields {
+		key, value := "eta", f
+		if kv := strings.SplitN(f, "=", 2); len(kv) == 2 {
+			key, value = strings.ToLower(kv[0]), kv[1]
+		} else if strings.ToLower(f) == "auto" {
+			continue
+		}
+		switch key {
+		case "eta":
+			m := etaOffsetRegexp.FindStringSubmatch(value)
+			if m == nil {
+				return errors.New("Invalid ETA offset, e.g., +0130: " + value)
+			}
+			h, _ := strconv.Atoi(m[1])
+			min, _ := strconv.Atoi(m[2])
+			t := depart.Add(time.Duration(h)*time.Hour + time.Duration(min)*time.Minute)
+			w.Wx.Eta = &t
+		case "wind":
+			v, n, err := parse.ParseWind(value)
+			if err != nil {
+				return err
+			}
+			wind, north = &v, n
+		case "temp":
+			t, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return errors.New("Invalid temperature: " + value)
+			}
+			w.Wx.Temp = &t
+		case "var":
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New("Invalid variation: " + value)
+			}
+			w.Wx.Variation = &v
+		default:
+			return errors.New("Unknown override, expected eta, wind, temp or var: " + f)
+		}
+	}
+	if wind != nil {
+		var variation *float64
+		if w.Wx.Variation != nil {
+			v := float64(*w.Wx.Variation)
+			variation = &v
+		}
+		t, err := windToTrue(*wind, north, variation)
+		if err != nil {
+			return err
+		}
+		w.Wx.Wind = &t
+	}
+	return nil
+}
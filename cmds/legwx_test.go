@@ -0,0 +1,34 @@
+package cmds
+
+import (
+	"github.com/cragcraig/flight/plan"
+	"testing"
+	"time"
+)
+
+func TestReannotateEta(t *testing.T) {
+	eta := time.Date(2026, 10, 18, 18, 30, 0, 0, time.UTC)
+	v := 8
+	annotated := plan.Weather{Eta: &eta, Variation: &v, Metar: "KBDU 181815Z 26012KT 10SM CLR 06/M08 A3012"}
+
+	// Everything was looked up, so it's all looked up again
+	w := plan.Waypoint{Name: "KBDU", Kind: plan.Origin, Wx: annotated}
+	later := eta.Add(3 * time.Hour)
+	w.Wx.Eta = &later
+	reannotateEta(&w, plan.Weather{}, annotated)
+	if w.Wx.Variation != nil || len(w.Wx.Metar) != 0 || len(w.Wx.Taf) != 0 {
+		t.Errorf("Got %+v, want the variation and surface weather cleared", w.Wx)
+	}
+	if !w.Wx.Eta.Equal(later) {
+		t.Errorf("ETA: got %s, want the override %s", w.Wx.Eta, later)
+	}
+
+	// Weather from the plan and overrides are kept
+	override := 9
+	w = plan.Waypoint{Name: "KBDU", Kind: plan.Origin, Wx: annotated}
+	w.Wx.Eta, w.Wx.Variation = &later, &override
+	reannotateEta(&w, plan.Weather{Metar: annotated.Metar}, annotated)
+	if w.Wx.Variation != &override || w.Wx.Metar != annotated.Metar {
+		t.Errorf("Got %+v, want the overridden variation and planned METAR", w.Wx)
+	}
+}
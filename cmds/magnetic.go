@@ -0,0 +1,105 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/wmm"
+	"math"
+	"time"
+)
+
+// Magnetic variation from the World Magnetic Model when WMM.COF is present,
+// otherwise as published for airports
+type variationSource struct {
+	model *wmm.Model
+	apts  *data.Apts
+}
+
+// Warns if neither source is available
+func loadVariationSource() variationSource {
+	v := variationSource{}
+	if model, err := wmm.Load(); err == nil {
+		v.model = &model
+	} else if apts, aptErr := data.LoadApts(); aptErr == nil {
+		v.apts = &apts
+	} else {
+		warnf("Magnetic variation unavailable: %s", err)
+	}
+	return v
+}
+
+// Variation (west positive) now at a position, which is looked up as an
+// airport by name when the magnetic model is unavailable
+func (v variationSource) At(name string, pos geo.Coord, altFt float64) (float64, error) {
+	if v.model != nil {
+		return v.model.Variation(pos, altFt, time.Now())
+	} else if v.apts != nil {
+		if apt, err := v.apts.GetApt(name); err == nil && apt.Variation != nil {
+			return float64(*apt.Variation), nil
+		}
+		return math.NaN(), errors.New("Magnetic variation unavailable for " + name + ", add WMM.COF for positions other than airports")
+	}
+	return math.NaN(), errors.New("Magnetic variation unavailable, add WMM.COF or APT.txt")
+}
+
+// As At, but nil with a warning if unavailable
+func (v variationSource) Try(name string, pos geo.Coord, altFt float64) *float64 {
+	variation, err := v.At(name, pos, altFt)
+	if err != nil {
+		if v.model != nil || v.apts != nil {
+			warnf("%s", err)
+		}
+		return nil
+	}
+	return &variation
+}
+
+// e.g., 8.2E
+func formatVariation(v float64) string {
+	if v > 0 {
+		return fmt.Sprintf("%.1fW", v)
+	}
+	return fmt.Sprintf("%.1fE", math.Abs(v))
+}
+
+// True direction, and magnetic if the variation is known, e.g., 162T 154M
+func formatDirection(deg float64, variation *float64) string {
+	s := fmt.Sprintf("%03dT", round(geo.Wrap360(deg))%360)
+	if variation != nil {
+		s += fmt.Sprintf(" %03dM", round(geo.TrueToMagnetic(deg, *variation))%360)
+	}
+	return s
+}
+
+// As formatDirection, to a tenth of a degree
+func formatDirectionTenths(deg float64, variation *float64) string {
+	s := fmt.Sprintf("%.1fT", geo.Wrap360(deg))
+	if variation != nil {
+		s += fmt.Sprintf(" %.1fM", geo.TrueToMagnetic(deg, *variation))
+	}
+	return s
+}
+
+// Converts a magnetic direction to true, which requires the variation
+func directionToTrue(deg float64, north geo.North, variation *float64, what string) (float64, error) {
+	if north == geo.TrueNorth {
+		return deg, nil
+	} else if variation == nil {
+		return math.NaN(), errors.New("Magnetic " + what + " requires the magnetic variation")
+	}
+	return geo.MagneticToTrue(deg, *variation), nil
+}
+
+// Wind vector (see parse.ParseGeoVect) with its direction converted to true
+func windToTrue(wind geo.Vect, north geo.North, variation *float64) (geo.Vect, error) {
+	if north == geo.TrueNorth || wind.IsOrigin() {
+		return wind, nil
+	}
+	dir, err := directionToTrue(geo.Rad2Compass(wind.AsAngle()), north, variation, "wind direction")
+	if err != nil {
+		return geo.Vect{}, err
+	}
+	return geo.HeadingFromAngle(geo.Compass2Rad(dir)).Mult(wind.Magnitude()), nil
+}
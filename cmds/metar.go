@@ -13,28 +13,46 @@ import (
 // TODO: Support passing as a flag
 const recency_upper_bound = 24
 
-func printMetars(metars []metar.Metar) error {
+const decodeFlag = "--decode"
+
+func printMetars(metars []metar.Metar, decode bool) error {
 	if len(metars) == 0 {
 		return errors.New("no results within parameters")
 	}
-	for _, m := range metars {
+	for i, m := range metars {
+		if !decode {
+			fmt.Println(m)
+			continue
+		}
+		if i != 0 {
+			fmt.Println("")
+		}
 		fmt.Println(m)
+		if r, err := m.Decode(); err != nil {
+			fmt.Println(err)
+		} else {
+			for _, l := range r.Describe() {
+				fmt.Println(l)
+			}
+		}
 	}
 	return nil
 }
 
 func MetarCmd(cmd CommandEntry, argv []string) error {
+	decode, argv := popFlag(argv, decodeFlag)
 	if len(argv) < 1 {
 		return cmd.getUsageError()
 	}
-	if metars, err := metar.QueryStations(argv, recency_upper_bound, true); err != nil {
+	if metars, err := metar.QueryStations(argv, recency_upper_bound); err != nil {
 		return err
 	} else {
-		return printMetars(metars)
+		return printMetars(metars, decode)
 	}
 }
 
 func MetarHistoryCmd(cmd CommandEntry, argv []string) error {
+	decode, argv := popFlag(argv, decodeFlag)
 	if len(argv) < 2 {
 		return cmd.getUsageError()
 	}
@@ -42,7 +60,7 @@ func MetarHistoryCmd(cmd CommandEntry, argv []string) error {
 	if err != nil || hours <= 0 {
 		return errors.New("Invalid hours, must be a positive integer: " + argv[0])
 	}
-	if metars, err := metar.QueryStations(argv[1:len(argv)], float64(hours), false); err != nil {
+	if metars, err := metar.QueryHistory(argv[1:len(argv)], float64(hours)); err != nil {
 		return err
 	} else {
 		mm := make(map[string][]metar.Metar)
@@ -56,7 +74,7 @@ func MetarHistoryCmd(cmd CommandEntry, argv []string) error {
 			} else {
 				fmt.Println("")
 			}
-			if err := printMetars(v); err != nil {
+			if err := printMetars(v, decode); err != nil {
 				fmt.Println(k + ": " + err.Error())
 			}
 		}
@@ -65,6 +83,7 @@ func MetarHistoryCmd(cmd CommandEntry, argv []string) error {
 }
 
 func MetarRadiusCmd(cmd CommandEntry, argv []string) error {
+	decode, argv := popFlag(argv, decodeFlag)
 	if len(argv) != 2 {
 		return cmd.getUsageError()
 	}
@@ -76,21 +95,19 @@ func MetarRadiusCmd(cmd CommandEntry, argv []string) error {
 		// STATION RADIUS
 		if natfix, err := data.LoadNatfix(); err != nil {
 			return err
-		} else if metars, err := metar.QueryStationRadius(natfix, argv[0], radius, recency_upper_bound, true); err != nil {
+		} else if metars, err := metar.QueryStationRadius(natfix, argv[0], radius, recency_upper_bound); err != nil {
 			return err
 		} else {
-			return printMetars(metars)
+			return printMetars(metars, decode)
 		}
 	} else {
 		// LON,LAT RADIUS
-		var lat, lon float64
-		if _, err := geo.ParseLatLon(argv[0]); err != nil {
+		if c, err := geo.ParseLatLon(argv[0]); err != nil {
 			return err
-		}
-		if metars, err := metar.QueryRadius(geo.NewCoord(lat, lon), radius, recency_upper_bound, true); err != nil {
+		} else if metars, err := metar.QueryRadius(c, radius, recency_upper_bound); err != nil {
 			return err
 		} else {
-			return printMetars(metars)
+			return printMetars(metars, decode)
 		}
 	}
 }
@@ -1,14 +1,24 @@
 package cmds
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cragcraig/flight/data"
 	"github.com/cragcraig/flight/geo"
 	"github.com/cragcraig/flight/parse"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 )
 
+const rhumbFlag = "--rhumb"
+
 func DistCmd(cmd CommandEntry, argv []string) error {
-	if len(argv) != 2 {
+	rhumb, argv := popFlag(argv, rhumbFlag)
+	if isRouteArgs(argv) {
+		return distRoute(argv, rhumb)
+	} else if len(argv) != 2 {
 		return cmd.getUsageError()
 	}
 
@@ -18,34 +28,90 @@ func DistCmd(cmd CommandEntry, argv []string) error {
 		return err
 	} else if c2, err := parse.ParsePos(natfix, argv[1]); err != nil {
 		return err
-	} else if course1, err := geo.InitialHeadingCompass(c1, c2); err != nil {
-		return err
-	} else if course2, err := geo.InitialHeadingCompass(c2, c1); err != nil {
+	} else if g, err := geo.Inverse(c1, c2); err != nil {
 		return err
 	} else {
-		fmt.Printf("       Distance: %.2f NM\n", geo.GlobeDistNM(c1, c2))
-		fmt.Printf("Initial Heading: %.1f\n", course1)
-		fmt.Printf("  Final Heading: %.1f\n", geo.Wrap360(course2-180))
+		src := loadVariationSource()
+		v1, v2 := src.Try(argv[0], c1, 0), src.Try(argv[1], c2, 0)
+		fmt.Printf("       Distance: %.2f NM\n", g.DistNM)
+		fmt.Printf("Initial Heading: %s\n", formatDirectionTenths(g.InitialAzimuth, v1))
+		fmt.Printf("  Final Heading: %s\n", formatDirectionTenths(g.FinalAzimuth, v2))
+		if v1 != nil && v2 != nil {
+			fmt.Printf("        Mag Var: %s at origin, %s at destination\n", formatVariation(*v1), formatVariation(*v2))
+		}
+		fmt.Printf("    Earth Model: %s\n", geo.DefaultModel)
+		if rhumb {
+			return printRhumbComparison(c1, c2, g, v1)
+		}
 		return nil
 	}
 }
 
+func distRoute(argv []string, rhumb bool) error {
+	if rhumb {
+		return errors.New("Rhumb lines are not supported for routes")
+	}
+	if natfix, err := data.LoadNatfix(); err != nil {
+		return err
+	} else if route, err := loadRoute(natfix, argv); err != nil {
+		return err
+	} else {
+		return printRouteDist(route)
+	}
+}
+
+// Rhumb line alongside the great circle, with the differences between them
+func printRhumbComparison(c1, c2 geo.Coord, g geo.Geodesic, variation *float64) error {
+	dist, bearing, err := geo.RhumbInverse(c1, c2)
+	if err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Printf(" Rhumb Distance: %.2f NM (%+.2f NM, %+.3f%%)\n", dist, dist-g.DistNM, 100*(dist-g.DistNM)/g.DistNM)
+	fmt.Printf("  Rhumb Heading: %s (%+.1f from initial)\n", formatDirectionTenths(bearing, variation), geo.Wrap360(bearing-g.InitialAzimuth+180)-180)
+	return nil
+}
+
 func CoordCmd(cmd CommandEntry, argv []string) error {
+	formatFlag, argv, err := popFlagValue(argv, "--format")
+	if err != nil {
+		return err
+	}
 	if len(argv) != 1 {
 		return cmd.getUsageError()
 	}
+	formats := []geo.CoordFormat{geo.FormatDecimal}
+	if formatFlag != nil && strings.ToLower(*formatFlag) == "all" {
+		formats = []geo.CoordFormat{geo.FormatDecimal, geo.FormatDMS, geo.FormatDDM, geo.FormatNASR, geo.FormatUTM, geo.FormatMGRS}
+	} else if formatFlag != nil {
+		f, err := geo.ParseCoordFormat(*formatFlag)
+		if err != nil {
+			return err
+		}
+		formats = []geo.CoordFormat{f}
+	}
 
 	if natfix, err := data.LoadNatfix(); err != nil {
 		return err
 	} else if c, err := parse.ParsePos(natfix, argv[0]); err != nil {
 		return err
 	} else {
-		fmt.Println(c)
+		for _, f := range formats {
+			s, err := c.Format(f)
+			if err != nil {
+				return err
+			}
+			fmt.Println(s)
+		}
 		return nil
 	}
 }
 
 func AptCmd(cmd CommandEntry, argv []string) error {
+	types, argv, err := popFacilityTypes(argv)
+	if err != nil {
+		return err
+	}
 	if len(argv) != 1 {
 		return cmd.getUsageError()
 	}
@@ -54,10 +120,154 @@ func AptCmd(cmd CommandEntry, argv []string) error {
 		return err
 	} else if apt, err := apts.GetApt(argv[0]); err != nil {
 		return err
+	} else if !apt.IsType(types) {
+		return fmt.Errorf("%s is a %s", apt.Id, apt.Type)
 	} else {
-		fmt.Printf("Lat, Lon:  %s\n", apt.Coord)
-		fmt.Printf("Altitude:  %d ft\n", apt.Alt)
-		fmt.Printf(" Mag Var:  %d\n", apt.Variation)
+		printApt(apt)
 		return nil
 	}
 }
+
+// Removes --type TYPE[,TYPE...] from argv, nil types if not present
+func popFacilityTypes(argv []string) ([]data.FacilityType, []string, error) {
+	typeFlag, argv, err := popFlagValue(argv, "--type")
+	if err != nil || typeFlag == nil {
+		return nil, argv, err
+	}
+	types, err := data.ParseFacilityTypes(*typeFlag)
+	return types, argv, err
+}
+
+// Default number of facilities listed by nearest
+const default_nearest_count = 5
+
+func NearestCmd(cmd CommandEntry, argv []string) error {
+	types, argv, err := popFacilityTypes(argv)
+	if err != nil {
+		return err
+	}
+	if len(argv) < 1 || len(argv) > 2 {
+		return cmd.getUsageError()
+	}
+	count := default_nearest_count
+	if len(argv) == 2 {
+		if count, err = strconv.Atoi(argv[1]); err != nil || count <= 0 {
+			return errors.New("Invalid count, must be a positive integer: " + argv[1])
+		}
+	}
+
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	pos, err := parse.ParsePos(natfix, argv[0])
+	if err != nil {
+		return err
+	}
+	apts, err := data.LoadApts()
+	if err != nil {
+		return err
+	}
+	nearest, err := apts.NearestN(pos, count, types)
+	if err != nil {
+		return err
+	} else if len(nearest) == 0 {
+		return errors.New("No matching landing facilities")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tNAME\tDIST\tBRG\tELEV\t")
+	for _, apt := range nearest {
+		brg := "-"
+		if b, err := geo.CourseCompass(pos, apt.Coord); err == nil && geo.DistanceNM(pos, apt.Coord) >= 0.05 {
+			brg = fmt.Sprintf("%03dT", round(b)%360)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f\t%s\t%d ft\t\n",
+			apt.Id, apt.Type, apt.Name, geo.DistanceNM(pos, apt.Coord), brg, apt.Alt)
+	}
+	return w.Flush()
+}
+
+var aptStatus = map[string]string{
+	"O":  "Operational",
+	"CI": "Closed indefinitely",
+	"CP": "Closed permanently",
+}
+
+func printApt(apt data.Apt) {
+	fmt.Printf("%s  %s\n", apt.Ident(), apt.Name)
+	fmt.Printf("      Type:  %s\n", apt.Type)
+	if len(apt.City) != 0 {
+		fmt.Printf("  Location:  %s, %s\n", apt.City, apt.State)
+	}
+	if status, exists := aptStatus[apt.Status]; exists && apt.Status != "O" {
+		fmt.Printf("    Status:  %s\n", status)
+	}
+	fmt.Printf("  Lat, Lon:  %s\n", apt.Coord)
+	fmt.Printf("  Altitude:  %d ft\n", apt.Alt)
+	if apt.Variation != nil {
+		fmt.Printf("   Mag Var:  %s\n", formatVariation(float64(*apt.Variation)))
+	}
+	if apt.PatternAgl != 0 {
+		fmt.Printf("   Pattern:  %d ft (%d ft AGL)\n", apt.Alt+apt.PatternAgl, apt.PatternAgl)
+	}
+	if len(apt.Ctaf) != 0 {
+		fmt.Printf("      CTAF:  %s\n", apt.Ctaf)
+	}
+	if len(apt.Unicom) != 0 {
+		fmt.Printf("    UNICOM:  %s\n", apt.Unicom)
+	}
+	if len(apt.FuelTypes) != 0 {
+		fmt.Printf("      Fuel:  %s\n", strings.Join(apt.FuelTypes, ", "))
+	}
+	for i, a := range apt.Attendance {
+		label := ""
+		if i == 0 {
+			label = "Attended:"
+		}
+		fmt.Printf("%10s  %s\n", label, a)
+	}
+
+	if len(apt.Runways) != 0 {
+		fmt.Println("")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RUNWAY\tSIZE\tSURFACE\tEND\tHDG\tDISPLACED\tTRAFFIC\tELEV\t")
+		for _, r := range apt.Runways {
+			size := "-"
+			if r.LengthFt != 0 {
+				size = fmt.Sprintf("%dx%d", r.LengthFt, r.WidthFt)
+			}
+			for i, e := range r.Ends {
+				id, sz, surface := "", "", ""
+				if i == 0 {
+					id, sz, surface = r.Id, size, r.Surface
+				}
+				hdg, displaced, traffic, elev := "-", "", "Left", "-"
+				if e.TrueHeading != 0 {
+					hdg = fmt.Sprintf("%03dT", e.TrueHeading)
+				}
+				if e.DisplacedFt != 0 {
+					displaced = fmt.Sprintf("%d ft", e.DisplacedFt)
+				}
+				if e.RightTraffic {
+					traffic = "Right"
+				}
+				if e.Elevation != nil {
+					elev = fmt.Sprintf("%.0f ft", *e.Elevation)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", id, sz, surface, e.Id, hdg, displaced, traffic, elev)
+			}
+		}
+		w.Flush()
+	}
+	for _, a := range apt.Arresting {
+		fmt.Printf("Arresting system: %s on runway %s\n", a.Type, a.End)
+	}
+
+	if len(apt.Remarks) != 0 {
+		fmt.Println("")
+		fmt.Println("Remarks:")
+		for _, r := range apt.Remarks {
+			fmt.Printf("  %s\n", r.Text)
+		}
+	}
+}
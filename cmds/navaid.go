@@ -0,0 +1,117 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+func NavaidCmd(cmd CommandEntry, argv []string) error {
+	altFlag, argv, err := popFlagValue(argv, "--alt")
+	if err != nil {
+		return err
+	}
+	if len(argv) == 1 && altFlag == nil {
+		return printNavaids(argv[0])
+	} else if len(argv) == 2 && altFlag != nil {
+		return printCoveringVors(argv[0], argv[1], *altFlag)
+	}
+	return cmd.getUsageError()
+}
+
+func printNavaids(id string) error {
+	navaids, err := data.LoadNavaids()
+	if err != nil {
+		return err
+	}
+	matches, err := navaids.Get(id)
+	if err != nil {
+		return err
+	}
+	src := loadVariationSource()
+	for i, nav := range matches {
+		if i != 0 {
+			fmt.Println("")
+		}
+		fmt.Printf("%s  %s %s\n", nav.Id, nav.Name, nav.Type)
+		if len(nav.City) != 0 {
+			fmt.Printf("   Location:  %s, %s\n", nav.City, nav.State)
+		}
+		fmt.Printf("   Lat, Lon:  %s\n", nav.Coord)
+		fmt.Printf("  Elevation:  %d ft\n", nav.Elevation)
+		fmt.Printf("  Frequency:  %s\n", nav.FrequencyString())
+		if len(nav.Channel) != 0 {
+			fmt.Printf("    Channel:  %s\n", nav.Channel)
+		}
+		fmt.Printf("      Ident:  %s\n", data.Morse(nav.Id))
+		slaved := formatVariation(float64(nav.Variation))
+		if nav.VariationYear != 0 {
+			slaved += fmt.Sprintf(" in %d", nav.VariationYear)
+		}
+		if v, err := src.At(nav.Id, nav.Coord, float64(nav.Elevation)); err == nil {
+			slaved += fmt.Sprintf(", %s now", formatVariation(v))
+		}
+		fmt.Printf("    Mag Var:  %s\n", slaved)
+		if len(nav.Class) != 0 {
+			fmt.Printf("      Class:  %s\n", nav.Class)
+		}
+		if nav.ServiceVolume != data.UnknownVolume {
+			fmt.Printf("     Volume:  %s (%s), %s above the facility\n",
+				nav.ServiceVolume, string(nav.ServiceVolume), nav.ServiceVolume.Limits())
+		}
+		fmt.Printf("     Status:  %s\n", nav.Status)
+	}
+	return nil
+}
+
+// Operational VORs usable for the whole leg at an altitude
+func printCoveringVors(origin, dest, alt string) error {
+	altFt, err := strconv.Atoi(alt)
+	if err != nil || altFt < 0 {
+		return errors.New("Invalid altitude, must be a non-negative integer: " + alt)
+	}
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	start, err := parse.ParsePos(natfix, origin)
+	if err != nil {
+		return err
+	}
+	end, err := parse.ParsePos(natfix, dest)
+	if err != nil {
+		return err
+	}
+	navaids, err := data.LoadNavaids()
+	if err != nil {
+		return err
+	}
+	covering, err := navaids.Covering(start, end, float64(altFt))
+	if err != nil {
+		return err
+	} else if len(covering) == 0 {
+		return fmt.Errorf("No VOR service volume covers the whole leg at %d ft", altFt)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tFREQ\tCLASS\tRANGE\tMAX DIST\tRADIAL\t")
+	for _, nav := range covering {
+		max, err := nav.MaxDistNM(start, end)
+		if err != nil {
+			return err
+		}
+		// Radial to the midpoint of the leg, relative to the slaved variation
+		radial := "-"
+		if b, err := geo.CourseCompass(nav.Coord, geo.IntermediatePoint(start, end, 0.5)); err == nil {
+			radial = fmt.Sprintf("%03d", round(geo.TrueToMagnetic(b, float64(nav.Variation)))%360)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.0f NM\t%.1f NM\t%s\t\n",
+			nav.Id, nav.Type, nav.FrequencyString(), string(nav.ServiceVolume),
+			nav.ServiceVolume.RangeNM(float64(altFt-nav.Elevation)), max, radial)
+	}
+	return w.Flush()
+}
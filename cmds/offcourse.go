@@ -0,0 +1,88 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"math"
+	"strconv"
+)
+
+// Distance ahead on course to rejoin it, unless given by --within
+const default_rejoin_nm = 10
+
+func OffCourseCmd(cmd CommandEntry, argv []string) error {
+	withinFlag, argv, err := popFlagValue(argv, "--within")
+	if err != nil {
+		return err
+	}
+	if len(argv) != 3 {
+		return cmd.getUsageError()
+	}
+	within := float64(default_rejoin_nm)
+	if withinFlag != nil {
+		if within, err = strconv.ParseFloat(*withinFlag, 64); err != nil || within <= 0 {
+			return errors.New("Invalid rejoin distance, must be a positive number of NM: " + *withinFlag)
+		}
+	}
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	pos := [3]geo.Coord{}
+	for i, a := range argv {
+		if pos[i], err = parse.ParsePos(natfix, a); err != nil {
+			return err
+		}
+	}
+	origin, dest, p := pos[0], pos[1], pos[2]
+	if origin == dest {
+		return errors.New("Origin and destination are the same location")
+	}
+
+	g, err := geo.Inverse(origin, dest)
+	if err != nil {
+		return err
+	}
+	leg := g.DistNM
+	// The foot of the perpendicular from p is found on the sphere, then both
+	// distances to it are measured with the earth model, as is the leg
+	alongSphere := geo.AlongTrackNM(origin, dest, p)
+	foot := geo.IntermediatePoint(origin, dest, alongSphere/geo.GlobeDistNM(origin, dest))
+	xt := math.Copysign(geo.DistanceNM(p, foot), geo.CrossTrackNM(origin, dest, p))
+	along := math.Copysign(geo.DistanceNM(origin, foot), alongSphere)
+	side := "right"
+	if xt < 0 {
+		side = "left"
+	}
+	variation := loadVariationSource().Try(argv[2], p, 0)
+	fmt.Printf("  Cross Track:  %.2f NM %s of course\n", math.Abs(xt), side)
+	fmt.Printf("  Along Track:  %.1f NM flown\n", along)
+	fmt.Printf("    Remaining:  %.1f NM\n", leg-along)
+	cpa := geo.ClosestPoint(origin, dest, p)
+	fmt.Printf("Closest Point:  %s, %.2f NM away\n", cpa, geo.DistanceNM(p, cpa))
+
+	// Aim for the point on course the rejoin distance ahead, or the destination
+	ahead := math.Max(along, 0) + within
+	if ahead >= leg {
+		if heading, err := geo.CourseCompass(p, dest); err == nil {
+			fmt.Printf("       Direct:  %s, %.1f NM to destination\n", formatDirection(heading, variation), geo.DistanceNM(p, dest))
+		}
+		return nil
+	}
+	target := geo.IntermediatePoint(origin, dest, ahead/leg)
+	intercept, err := geo.CourseCompass(p, target)
+	if err != nil {
+		return err
+	}
+	course, err := geo.CourseCompass(target, dest)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("    Intercept:  %s to rejoin within %.1f NM (%.0f to course %s)\n",
+		formatDirection(intercept, variation), geo.DistanceNM(p, target),
+		math.Abs(geo.Wrap360(intercept-course+180)-180), formatDirection(course, variation))
+	return nil
+}
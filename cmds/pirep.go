@@ -0,0 +1,101 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"github.com/cragcraig/flight/pirep"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const pirep_default_hours = 3
+
+type pirepEntry struct {
+	pirep  pirep.Pirep
+	report pirep.Report
+	dist   float64
+	age    time.Duration
+}
+
+func PirepCmd(cmd CommandEntry, argv []string) error {
+	all, argv := popFlag(argv, "--all")
+	if len(argv) != 2 && len(argv) != 3 {
+		return cmd.getUsageError()
+	}
+	radius, err := strconv.Atoi(argv[1])
+	if err != nil || radius <= 0 {
+		return errors.New("Invalid radius, must be a positive integer: " + argv[1])
+	}
+	hours := pirep_default_hours
+	if len(argv) == 3 {
+		if hours, err = strconv.Atoi(argv[2]); err != nil || hours <= 0 {
+			return errors.New("Invalid hours, must be a positive integer: " + argv[2])
+		}
+	}
+
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	ref, err := parse.ParsePos(natfix, argv[0])
+	if err != nil {
+		return err
+	}
+	pireps, err := pirep.QueryRadius(ref, radius, float64(hours))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := []pirepEntry{}
+	for _, p := range pireps {
+		r, err := p.Decode()
+		if err != nil || (!all && !r.HasTurbulence() && !r.HasIcing()) {
+			continue
+		}
+		age := time.Duration(math.MaxInt64)
+		if t, err := p.ObservedAt(); err == nil {
+			age = now.Sub(t)
+		}
+		entries = append(entries, pirepEntry{p, r, geo.GlobeDistNM(ref, p.Coord()), age})
+	}
+	if len(entries) == 0 {
+		return errors.New("no results within parameters")
+	}
+	// Nearest first, most recent first within the same 10 NM band
+	sort.Slice(entries, func(i, j int) bool {
+		bi, bj := int(entries[i].dist/10), int(entries[j].dist/10)
+		if bi != bj {
+			return bi < bj
+		}
+		return entries[i].age < entries[j].age
+	})
+	return printPireps(entries)
+}
+
+func printPireps(entries []pirepEntry) error {
+	fmt.Printf("%-5s  %-5s  %-6s  %-6s  %-20s  %-20s\n", "DIST", "AGE", "ALT", "TYPE", "TURBULENCE", "ICING")
+	for _, e := range entries {
+		alt := e.report.FlightLevel
+		if e.report.AltitudeFt >= 0 {
+			alt = fmt.Sprintf("%dft", e.report.AltitudeFt)
+		}
+		age := "-"
+		if e.age != time.Duration(math.MaxInt64) {
+			age = fmt.Sprintf("%dm", int(e.age.Minutes()))
+		}
+		kind := e.report.AircraftType
+		if e.report.Urgent {
+			kind = "UUA " + kind
+		}
+		fmt.Printf("%-5s  %-5s  %-6s  %-6s  %-20s  %-20s\n",
+			fmt.Sprintf("%dNM", round(e.dist)), age, alt, kind, e.report.Turbulence, e.report.Icing)
+		fmt.Printf("  %s\n", e.pirep)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package cmds
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Positional arguments giving a route rather than an ORIGIN DEST pair, either
+// as separate arguments or a single one, e.g., KBDU DVV V8 AKO KCYS
+func isRouteArgs(argv []string) bool {
+	return len(argv) > 2 || (len(argv) == 1 && len(strings.Fields(argv[0])) > 1)
+}
+
+func loadRoute(natfix data.Natfix, argv []string) (parse.Route, error) {
+	airways, awyErr := data.LoadAirways()
+	// Direct routes don't need the airway database
+	route, err := parse.ParseRoute(natfix, airways, strings.Join(argv, " "))
+	if err != nil && awyErr != nil {
+		return nil, fmt.Errorf("%s\nAirways unavailable: %s", err, awyErr)
+	}
+	return route, err
+}
+
+// A leg of a route between consecutive points
+type routeLeg struct {
+	from, to parse.RoutePoint
+	g        geo.Geodesic
+}
+
+func routeLegs(route parse.Route) ([]routeLeg, error) {
+	legs := []routeLeg{}
+	for i := 1; i < len(route); i++ {
+		g, err := geo.Inverse(route[i-1].Pos, route[i].Pos)
+		if err != nil {
+			return nil, fmt.Errorf("%s to %s: %s", route[i-1].Name, route[i].Name, err)
+		}
+		legs = append(legs, routeLeg{from: route[i-1], to: route[i], g: g})
+	}
+	return legs, nil
+}
+
+// e.g., V8, or - if flown direct
+func (l routeLeg) via() string {
+	if len(l.to.Airway) == 0 {
+		return "-"
+	}
+	return l.to.Airway
+}
+
+// MEA, MOCA and changeover point columns, blank if direct or not published
+func (l routeLeg) airwayColumns() (string, string, string) {
+	mea, moca, cop := "", "", ""
+	if s := l.to.Segment; s != nil {
+		if s.Mea != 0 {
+			mea = fmt.Sprintf("%d", s.Mea)
+		}
+		if s.Moca != 0 {
+			moca = fmt.Sprintf("%d", s.Moca)
+		}
+		if s.ChangeoverNM != 0 {
+			cop = fmt.Sprintf("%.0f NM", s.ChangeoverNM)
+		}
+	}
+	return mea, moca, cop
+}
+
+// Variation at the start of each leg, nil where unavailable
+func legVariations(legs []routeLeg, altFt float64) []*float64 {
+	src := loadVariationSource()
+	variations := []*float64{}
+	for _, l := range legs {
+		if v, err := src.At(l.from.Name, l.from.Pos, altFt); err == nil {
+			variations = append(variations, &v)
+		} else {
+			variations = append(variations, nil)
+		}
+	}
+	return variations
+}
+
+// Nearest VOR covering each leg at altFt, blank where none does. Nil if the
+// NAV database is unavailable.
+func legVors(legs []routeLeg, altFt float64) []string {
+	navaids, err := data.LoadNavaids()
+	if err != nil {
+		return nil
+	}
+	vors := []string{}
+	for _, l := range legs {
+		vors = append(vors, coveringVor(navaids, l.from.Pos, l.to.Pos, altFt))
+	}
+	return vors
+}
+
+func coveringVor(navaids data.Navaids, start, end geo.Coord, altFt float64) string {
+	if covering, err := navaids.Covering(start, end, altFt); err == nil && len(covering) != 0 {
+		return covering[0].Id
+	}
+	return ""
+}
+
+func printRouteDist(route parse.Route) error {
+	legs, err := routeLegs(route)
+	if err != nil {
+		return err
+	}
+	variations := legVariations(legs, 0)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FROM\tTO\tVIA\tDIST\tCOURSE\tMEA\tMOCA\tCOP\tTOTAL\t")
+	total := 0.0
+	for i, l := range legs {
+		total += l.g.DistNM
+		mea, moca, cop := l.airwayColumns()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f NM\t%s\t%s\t%s\t%s\t%.1f NM\t\n",
+			l.from.Name, l.to.Name, l.via(), l.g.DistNM,
+			formatDirection(l.g.InitialAzimuth, variations[i]), mea, moca, cop, total)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Printf(" Total Distance: %.2f NM\n", total)
+	if direct, err := geo.Inverse(route[0].Pos, route[len(route)-1].Pos); err == nil {
+		fmt.Printf("Direct Distance: %.2f NM (%+.2f NM)\n", direct.DistNM, total-direct.DistNM)
+	}
+	fmt.Printf("    Earth Model: %s\n", geo.DefaultModel)
+	return nil
+}
+
+// Wind correction for each leg of a route, with a wind for each leg or a
+// single wind for all of them
+func printRouteWindCorrection(route parse.Route, tas float64, winds []geo.Vect, altFt *float64) error {
+	legs, err := routeLegs(route)
+	if err != nil {
+		return err
+	}
+	alt := 0.0
+	if altFt != nil {
+		alt = *altFt
+	}
+	variations := legVariations(legs, alt)
+	// Only meaningful at a known altitude
+	var vors []string
+	if altFt != nil {
+		vors = legVors(legs, alt)
+	}
+	formatWind := func(wind geo.Vect, variation *float64) string {
+		return fmt.Sprintf("%d kts @ %s", round(wind.Magnitude()), formatDirection(geo.Rad2Compass(wind.AsAngle()), variation))
+	}
+	perLeg := len(winds) > 1
+	fmt.Printf("  TAS:  %d kts\n", round(tas))
+	if !perLeg {
+		fmt.Printf(" Wind:  %s\n", formatWind(winds[0], variations[0]))
+	}
+	fmt.Println("")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "FROM\tTO\tVIA\tDIST\t"
+	if perLeg {
+		header += "WIND\t"
+	}
+	header += "COURSE\tHEADING\tGS\tETE\tMEA\t"
+	if vors != nil {
+		header += "VOR\t"
+	}
+	fmt.Fprintln(w, header)
+	total, totalMin := 0.0, 0.0
+	belowMea := []string{}
+	for i, l := range legs {
+		wind := winds[0]
+		if perLeg {
+			wind = winds[i]
+		}
+		h, gs, err := geo.WindTriangle(geo.Compass2Rad(l.g.InitialAzimuth), tas, wind)
+		if err != nil {
+			return fmt.Errorf("%s to %s: %s", l.from.Name, l.to.Name, err)
+		}
+		ete := l.g.DistNM / (gs / 60)
+		total += l.g.DistNM
+		totalMin += ete
+		mea, _, _ := l.airwayColumns()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f NM\t", l.from.Name, l.to.Name, l.via(), l.g.DistNM)
+		if perLeg {
+			fmt.Fprintf(w, "%s\t", formatWind(wind, variations[i]))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d kts\t%.1f min\t%s\t",
+			formatDirection(l.g.InitialAzimuth, variations[i]),
+			formatDirection(geo.Rad2Compass(h), variations[i]),
+			round(gs), ete, mea)
+		if vors != nil {
+			fmt.Fprintf(w, "%s\t", vors[i])
+		}
+		fmt.Fprintln(w, "")
+		if s := l.to.Segment; altFt != nil && s != nil && s.Mea != 0 && alt < float64(s.Mea) {
+			belowMea = append(belowMea, fmt.Sprintf("%.0f ft is below the %s MEA of %d ft from %s to %s", alt, l.to.Airway, s.Mea, l.from.Name, l.to.Name))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Printf(" Distance:  %.1f NM\n", total)
+	fmt.Printf("      ETE:  %.1f min\n", totalMin)
+	for _, b := range belowMea {
+		warnf("Warning: %s", b)
+	}
+	return nil
+}
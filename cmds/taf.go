@@ -0,0 +1,102 @@
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/taf"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func printTafs(tafs []taf.Taf, decode bool) error {
+	if len(tafs) == 0 {
+		return errors.New("no results within parameters")
+	}
+	for i, t := range tafs {
+		if i != 0 {
+			fmt.Println("")
+		}
+		fmt.Println(t)
+		if !decode {
+			continue
+		}
+		f, err := t.Forecast()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println("")
+		for _, p := range f.Periods {
+			fmt.Println(p)
+		}
+		if c, err := f.ConditionsAt(time.Now()); err == nil {
+			fmt.Println("")
+			printTafConditionsTo(os.Stdout, "Now", c)
+		}
+	}
+	return nil
+}
+
+func printTafConditionsTo(w io.Writer, label string, c taf.Conditions) {
+	wind, vis, ceiling := "-", "-", "None"
+	if c.Wind != nil {
+		wind = c.Wind.String()
+	}
+	if c.Visibility != nil {
+		vis = c.Visibility.String()
+	}
+	if c.HasCeiling {
+		ceiling = fmt.Sprintf("%d ft", c.CeilingFt)
+	}
+	fmt.Fprintf(w, "%s: %s, %s, ceiling %s, %s\n", label, wind, vis, ceiling, c.FlightCategory)
+	for _, p := range c.Temporary {
+		fmt.Fprintf(w, "  %s\n", p)
+	}
+}
+
+func TafCmd(cmd CommandEntry, argv []string) error {
+	decode, argv := popFlag(argv, decodeFlag)
+	if len(argv) < 1 {
+		return cmd.getUsageError()
+	}
+	if tafs, err := taf.QueryStations(argv, recency_upper_bound); err != nil {
+		return err
+	} else {
+		return printTafs(tafs, decode)
+	}
+}
+
+func TafRadiusCmd(cmd CommandEntry, argv []string) error {
+	decode, argv := popFlag(argv, decodeFlag)
+	if len(argv) != 2 {
+		return cmd.getUsageError()
+	}
+	radius, err := strconv.Atoi(argv[1])
+	if err != nil || radius <= 0 {
+		return errors.New("Invalid radius, must be a positive integer: " + argv[1])
+	}
+	if !strings.ContainsRune(argv[0], ',') {
+		// STATION RADIUS
+		if natfix, err := data.LoadNatfix(); err != nil {
+			return err
+		} else if tafs, err := taf.QueryStationRadius(natfix, argv[0], radius, recency_upper_bound); err != nil {
+			return err
+		} else {
+			return printTafs(tafs, decode)
+		}
+	} else {
+		// LON,LAT RADIUS
+		if c, err := geo.ParseLatLon(argv[0]); err != nil {
+			return err
+		} else if tafs, err := taf.QueryRadius(c, radius, recency_upper_bound); err != nil {
+			return err
+		} else {
+			return printTafs(tafs, decode)
+		}
+	}
+}
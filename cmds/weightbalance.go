@@ -0,0 +1,231 @@
+package cmds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/aircraft"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ASCII envelope plot size, in characters
+const wb_plot_width = 60
+const wb_plot_height = 20
+
+// Plot markers for each envelope, in profile order, and the loading points
+const wb_envelope_marks = "#+*o"
+const wb_takeoff_mark = 'T'
+const wb_landing_mark = 'L'
+
+func WeightBalanceCmd(cmd CommandEntry, argv []string) error {
+	planFlag, argv, err := popFlagValue(argv, "--plan")
+	if err != nil {
+		return err
+	}
+	burnFlag, argv, err := popFlagValue(argv, "--burn")
+	if err != nil {
+		return err
+	}
+	if len(argv) < 1 || (planFlag != nil && burnFlag != nil) {
+		return cmd.getUsageError()
+	}
+	ac, err := aircraft.Load(argv[0])
+	if err != nil {
+		return err
+	}
+	wb := ac.WeightBalance
+	if wb == nil {
+		return errors.New("Aircraft profile " + ac.Id + " has no weight and balance data")
+	}
+
+	// Loads given as STATION=VALUE, any others are prompted for
+	loads := make(map[string]float64)
+	for _, a := range argv[1:] {
+		kv := strings.SplitN(a, "=", 2)
+		if len(kv) != 2 {
+			return cmd.getUsageError()
+		}
+		s, err := wb.Station(kv[0])
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil || v < 0 {
+			return errors.New("Invalid load, must be a non-negative number: " + a)
+		}
+		loads[s.Name] = v
+	}
+	in := bufio.NewReader(os.Stdin)
+	for _, s := range wb.Stations {
+		if _, exists := loads[s.Name]; exists {
+			continue
+		}
+		unit, max := "lbs", s.MaxLbs
+		if s.Fuel {
+			unit, max = "gal", ac.UsableGal
+		}
+		prompt := fmt.Sprintf("%s (%s) > ", s.Name, unit)
+		if max > 0 {
+			prompt = fmt.Sprintf("%s (%s, max %g) > ", s.Name, unit, max)
+		}
+		fields, err := promptLine(in, prompt)
+		if err != nil || len(fields) == 0 {
+			loads[s.Name] = 0
+		} else if v, err := strconv.ParseFloat(fields[0], 64); err != nil || v < 0 {
+			return errors.New("Invalid load, must be a non-negative number: " + fields[0])
+		} else {
+			loads[s.Name] = v
+		}
+	}
+
+	// Landing fuel is the takeoff fuel less the planned burn
+	burn := 0.0
+	if burnFlag != nil {
+		if burn, err = strconv.ParseFloat(*burnFlag, 64); err != nil || burn < 0 {
+			return errors.New("Invalid fuel burn, must be a non-negative number of gallons: " + *burnFlag)
+		}
+	} else if planFlag != nil {
+		p, err := loadPlan(planFlag)
+		if err != nil {
+			return err
+		}
+		log, err := p.NavLog(ac, 0)
+		if err != nil {
+			return err
+		}
+		burn = log.Total().Fuel
+	}
+
+	problems := []string{}
+	ramp := wb.Empty()
+	fmt.Printf("%s (%s)\n\n", ac.Name, ac.Id)
+	fmt.Printf("%-10s %8s %8s %10s\n", "STATION", "ARM", "LBS", "MOMENT")
+	fmt.Printf("%-10s %8.1f %8.0f %10.0f\n", "Empty", wb.EmptyArm, wb.EmptyLbs, wb.EmptyLbs*wb.EmptyArm)
+	fuelGal := 0.0
+	for _, s := range wb.Stations {
+		load := loads[s.Name]
+		lbs := load
+		name := s.Name
+		if s.Fuel {
+			fuelGal = load
+			lbs = wb.FuelLbs(load)
+			name = fmt.Sprintf("%s %ggal", s.Name, load)
+			if ac.UsableGal > 0 && load > ac.UsableGal {
+				problems = append(problems, fmt.Sprintf("%s exceeds %g gal usable", name, ac.UsableGal))
+			}
+		} else if s.MaxLbs > 0 && load > s.MaxLbs {
+			problems = append(problems, fmt.Sprintf("%s %g lbs exceeds the %g lbs limit", s.Name, load, s.MaxLbs))
+		}
+		ramp = wb.Load(ramp, s, load)
+		fmt.Printf("%-10s %8.1f %8.0f %10.0f\n", name, s.Arm, lbs, lbs*s.Arm)
+	}
+	fmt.Println("")
+
+	takeoff, landing := ramp, ramp
+	if fuel, err := wb.FuelStation(); err == nil {
+		taxi := math.Min(ac.TaxiGal, fuelGal)
+		takeoff = wb.Load(ramp, fuel, -taxi)
+		if burn > fuelGal-taxi {
+			problems = append(problems, fmt.Sprintf("Planned burn of %.1f gal exceeds the %.1f gal on board at takeoff", burn, fuelGal-taxi))
+			burn = fuelGal - taxi
+		}
+		landing = wb.Load(takeoff, fuel, -burn)
+		fmt.Printf("Taxi %.1f gal, planned burn %.1f gal\n", taxi, burn)
+	}
+	for _, c := range []struct {
+		name string
+		m    aircraft.Mass
+	}{{"Ramp", ramp}, {"Takeoff", takeoff}, {"Landing", landing}} {
+		status := []string{}
+		for _, e := range wb.Envelopes {
+			if e.Contains(c.m) {
+				status = append(status, e.Name+" OK")
+			} else {
+				status = append(status, e.Name+" OUT")
+			}
+		}
+		fmt.Printf("%-8s %6.0f lbs  CG %5.2f  %s\n", c.name, c.m.Lbs, c.m.Cg(), strings.Join(status, ", "))
+	}
+	// The first envelope is the one the aircraft must be flown within
+	for _, c := range []struct {
+		name string
+		m    aircraft.Mass
+	}{{"Takeoff", takeoff}, {"Landing", landing}} {
+		if !wb.Envelopes[0].Contains(c.m) {
+			problems = append(problems, fmt.Sprintf("%s weight and CG are outside the %s envelope", c.name, wb.Envelopes[0].Name))
+		}
+	}
+	fmt.Println("")
+	plotEnvelopes(wb.Envelopes, takeoff, landing)
+
+	if len(problems) != 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func plotEnvelopes(envelopes []aircraft.Envelope, takeoff, landing aircraft.Mass) {
+	// Plot bounds include every envelope and both points, with a margin
+	minX, maxX := math.Min(takeoff.Cg(), landing.Cg()), math.Max(takeoff.Cg(), landing.Cg())
+	minY, maxY := math.Min(takeoff.Lbs, landing.Lbs), math.Max(takeoff.Lbs, landing.Lbs)
+	for _, e := range envelopes {
+		for _, p := range e.Points {
+			minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+			minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+		}
+	}
+	dx, dy := (maxX-minX)*0.05, (maxY-minY)*0.05
+	minX, maxX, minY, maxY = minX-dx, maxX+dx, minY-dy, maxY+dy
+
+	grid := make([][]byte, wb_plot_height)
+	for i := range grid {
+		grid[i] = []byte(strings.Repeat(" ", wb_plot_width))
+	}
+	cell := func(x, y float64) (int, int) {
+		col := int(math.Round((x - minX) / (maxX - minX) * (wb_plot_width - 1)))
+		row := int(math.Round((maxY - y) / (maxY - minY) * (wb_plot_height - 1)))
+		return col, row
+	}
+	// Draw in reverse so the first envelope is on top
+	for i := len(envelopes) - 1; i >= 0; i-- {
+		mark := wb_envelope_marks[i%len(wb_envelope_marks)]
+		p := envelopes[i].Points
+		for j := range p {
+			a, b := p[j], p[(j+1)%len(p)]
+			steps := 2 * (wb_plot_width + wb_plot_height)
+			for s := 0; s <= steps; s++ {
+				f := float64(s) / float64(steps)
+				col, row := cell(a[0]+f*(b[0]-a[0]), a[1]+f*(b[1]-a[1]))
+				grid[row][col] = mark
+			}
+		}
+	}
+	col, row := cell(landing.Cg(), landing.Lbs)
+	grid[row][col] = wb_landing_mark
+	col, row = cell(takeoff.Cg(), takeoff.Lbs)
+	grid[row][col] = wb_takeoff_mark
+
+	for i, r := range grid {
+		label := ""
+		if i%5 == 0 || i == wb_plot_height-1 {
+			label = fmt.Sprintf("%.0f", maxY-float64(i)/(wb_plot_height-1)*(maxY-minY))
+		}
+		fmt.Printf("%6s |%s\n", label, string(r))
+	}
+	fmt.Printf("%6s +%s\n", "lbs", strings.Repeat("-", wb_plot_width))
+	mid := fmt.Sprintf("%.1f", (minX+maxX)/2)
+	left := fmt.Sprintf("%.1f", minX)
+	right := fmt.Sprintf("%.1f", maxX)
+	gap := (wb_plot_width - len(left) - len(mid) - len(right)) / 2
+	fmt.Printf("%6s  %s%s%s%s%s  CG (in)\n", "", left, strings.Repeat(" ", gap), mid,
+		strings.Repeat(" ", wb_plot_width-len(left)-len(mid)-len(right)-gap), right)
+	legend := []string{}
+	for i, e := range envelopes {
+		legend = append(legend, fmt.Sprintf("%c %s", wb_envelope_marks[i%len(wb_envelope_marks)], e.Name))
+	}
+	legend = append(legend, fmt.Sprintf("%c Takeoff", wb_takeoff_mark), fmt.Sprintf("%c Landing", wb_landing_mark))
+	fmt.Printf("\n%6s  %s\n", "", strings.Join(legend, "   "))
+}
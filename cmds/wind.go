@@ -6,24 +6,24 @@ import (
 	"github.com/cragcraig/flight/data"
 	"github.com/cragcraig/flight/geo"
 	"github.com/cragcraig/flight/parse"
-	"math"
+	"github.com/cragcraig/flight/winds"
 	"strconv"
+	"strings"
 )
 
-func heading(course, tas float64, wind geo.Vect) float64 {
-	return math.Asin(wind.Magnitude()*math.Sin(wind.AsAngle()-course)/tas) + course
-}
-
-func groundSpeed(course, heading, tas float64, wind geo.Vect) float64 {
-	return tas*math.Cos(heading-course) - wind.Magnitude()*math.Cos(wind.AsAngle()-course)
-}
-
 func round(v float64) int {
 	return int(v + 0.5)
 }
 
 func WindCorrectionRouteCmd(cmd CommandEntry, argv []string) error {
-	if len(argv) != 4 {
+	rhumb, argv := popFlag(argv, rhumbFlag)
+	altFlag, argv, err := popFlagValue(argv, "--alt")
+	if err != nil {
+		return err
+	}
+	if len(argv) > 2 && isRouteArgs(argv[2:]) {
+		return windCorrectionRoute(argv, altFlag, rhumb)
+	} else if len(argv) != 4 {
 		return cmd.getUsageError()
 	}
 
@@ -33,19 +33,198 @@ func WindCorrectionRouteCmd(cmd CommandEntry, argv []string) error {
 		return err
 	} else if dest, err := parse.ParsePos(natfix, argv[3]); err != nil {
 		return err
-	} else if wv, err := parse.ParseGeoVect(argv[1]); err != nil {
-		return err
 	} else if tas, err := strconv.ParseFloat(argv[0], 64); err != nil {
 		return err
-	} else if course, err := geo.InitialHeadingCompass(origin, dest); err != nil {
+	} else if g, err := geo.Inverse(origin, dest); err != nil {
 		return err
 	} else {
-		dist := geo.GlobeDistNM(origin, dest)
-		return windCorrectionInternal(geo.Compass2Rad(course), tas, wv, &dist)
+		printFacilities(argv[2], argv[3])
+		// Variation at the origin, where the initial course is flown from
+		variation := loadVariationSource().Try(argv[2], origin, altitudeOrZero(altFlag))
+		wv, err := parseWindArg(natfix, argv[1], altFlag, geo.IntermediatePoint(origin, dest, 0.5), variation)
+		if err != nil {
+			return err
+		}
+		if rhumb {
+			return windCorrectionRhumb(origin, dest, g, tas, wv, variation)
+		}
+		course, dist := g.InitialAzimuth, g.DistNM
+		if err := windCorrectionInternal(geo.Compass2Rad(course), tas, wv, &dist, variation); err != nil {
+			return err
+		}
+		if altFlag != nil {
+			if navaids, err := data.LoadNavaids(); err == nil {
+				if vor := coveringVor(navaids, origin, dest, altitudeOrZero(altFlag)); len(vor) != 0 {
+					fmt.Printf("      VOR:  %s\n", vor)
+				}
+			}
+		}
+		return nil
 	}
 }
 
+// Wind correction for each leg of a route string, with automatic winds
+// interpolated at the midpoint of each leg
+func windCorrectionRoute(argv []string, altFlag *string, rhumb bool) error {
+	if rhumb {
+		return errors.New("Rhumb lines are not supported for routes")
+	}
+	natfix, err := data.LoadNatfix()
+	if err != nil {
+		return err
+	}
+	tas, err := strconv.ParseFloat(argv[0], 64)
+	if err != nil {
+		return err
+	}
+	route, err := loadRoute(natfix, argv[2:])
+	if err != nil {
+		return err
+	}
+	origin, dest := route[0], route[len(route)-1]
+	printFacilities(origin.Name, dest.Name)
+	legWinds := []geo.Vect{}
+	if strings.ToLower(argv[1]) == "auto" {
+		forecast, altFt, err := loadWindsAloft(natfix, altFlag)
+		if err != nil {
+			return err
+		}
+		if legWinds, err = routeWinds(forecast, route, altFt); err != nil {
+			return err
+		}
+		fmt.Printf("%dft winds aloft at the midpoint of each leg\n\n", altFt)
+	} else {
+		variation := loadVariationSource().Try(origin.Name, origin.Pos, altitudeOrZero(altFlag))
+		wv, err := parseWindArg(natfix, argv[1], altFlag, geo.IntermediatePoint(origin.Pos, dest.Pos, 0.5), variation)
+		if err != nil {
+			return err
+		}
+		legWinds = append(legWinds, wv)
+	}
+	var altFt *float64
+	if altFlag != nil {
+		alt := altitudeOrZero(altFlag)
+		altFt = &alt
+	}
+	return printRouteWindCorrection(route, tas, legWinds, altFt)
+}
+
+// Forecast wind at the midpoint of each leg of a route
+func routeWinds(forecast winds.Forecast, route parse.Route, altFt int) ([]geo.Vect, error) {
+	legWinds := []geo.Vect{}
+	for i := 1; i < len(route); i++ {
+		aloft, err := forecast.At(geo.IntermediatePoint(route[i-1].Pos, route[i].Pos, 0.5), altFt)
+		if err != nil {
+			return nil, fmt.Errorf("%s to %s: %s", route[i-1].Name, route[i].Name, err)
+		}
+		legWinds = append(legWinds, aloft.Wind)
+	}
+	return legWinds, nil
+}
+
+// Facility types of the origin and destination, when found in the APT
+// database, so a heliport isn't mistaken for an airport
+func printFacilities(origin, dest string) {
+	apts, err := data.LoadApts()
+	if err != nil {
+		return
+	}
+	printed := false
+	for i, station := range []string{origin, dest} {
+		if apt, err := apts.GetApt(station); err == nil {
+			fmt.Printf("%9s:  %s, %s\n", []string{"Origin", "Dest"}[i], apt.Ident(), apt.Type)
+			printed = true
+		}
+	}
+	if printed {
+		fmt.Println("")
+	}
+}
+
+// Altitude given by --alt, or sea level
+func altitudeOrZero(alt *string) float64 {
+	if alt != nil {
+		if v, err := strconv.Atoi(*alt); err == nil && v >= 0 {
+			return float64(v)
+		}
+	}
+	return 0
+}
+
+// Great circle and rhumb line solutions, with the differences between them
+func windCorrectionRhumb(origin, dest geo.Coord, g geo.Geodesic, tas float64, wind geo.Vect, variation *float64) error {
+	rhumbDist, rhumbCourse, err := geo.RhumbInverse(origin, dest)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Great circle (initial course)")
+	if err := windCorrectionInternal(geo.Compass2Rad(g.InitialAzimuth), tas, wind, &g.DistNM, variation); err != nil {
+		return err
+	}
+	fmt.Println("\nRhumb line")
+	if err := windCorrectionInternal(geo.Compass2Rad(rhumbCourse), tas, wind, &rhumbDist, variation); err != nil {
+		return err
+	}
+	h1, gs1, _ := geo.WindTriangle(geo.Compass2Rad(g.InitialAzimuth), tas, wind)
+	h2, gs2, _ := geo.WindTriangle(geo.Compass2Rad(rhumbCourse), tas, wind)
+	fmt.Println("\nRhumb line difference")
+	fmt.Printf(" Distance:  %+.2f NM\n", rhumbDist-g.DistNM)
+	fmt.Printf("  Heading:  %+.1f\n", geo.Wrap360(geo.Rad2Compass(h2)-geo.Rad2Compass(h1)+180)-180)
+	fmt.Printf("      ETE:  %+.1f min\n", rhumbDist/(gs2/60)-g.DistNM/(gs1/60))
+	return nil
+}
+
+// Either an explicit SPEED@DIR or "auto" to use the winds aloft forecast
+// interpolated at pos and the altitude given by --alt
+func parseWindArg(natfix data.Natfix, arg string, alt *string, pos geo.Coord, variation *float64) (geo.Vect, error) {
+	if strings.ToLower(arg) != "auto" {
+		wind, north, err := parse.ParseWind(arg)
+		if err != nil {
+			return geo.Vect{}, err
+		}
+		return windToTrue(wind, north, variation)
+	}
+	forecast, altFt, err := loadWindsAloft(natfix, alt)
+	if err != nil {
+		return geo.Vect{}, err
+	}
+	aloft, err := forecast.At(pos, altFt)
+	if err != nil {
+		return geo.Vect{}, err
+	}
+	temp := ""
+	if aloft.HasTemp {
+		temp = fmt.Sprintf(", %.0f C", aloft.Temp)
+	}
+	fmt.Printf("%dft winds aloft: %d @ %dT%s\n\n",
+		altFt,
+		round(aloft.Wind.Magnitude()),
+		round(geo.Rad2Compass(aloft.Wind.AsAngle())),
+		temp)
+	return aloft.Wind, nil
+}
+
+// Winds aloft forecast and the altitude given by --alt to use it at
+func loadWindsAloft(natfix data.Natfix, alt *string) (winds.Forecast, int, error) {
+	if alt == nil {
+		return winds.Forecast{}, 0, errors.New("Automatic winds aloft require an altitude, e.g., --alt 9000")
+	}
+	altFt, err := strconv.Atoi(*alt)
+	if err != nil || altFt < 0 {
+		return winds.Forecast{}, 0, errors.New("Invalid altitude, must be a non-negative integer: " + *alt)
+	}
+	forecast, err := winds.Load(natfix)
+	if err != nil {
+		return winds.Forecast{}, 0, err
+	}
+	return forecast, altFt, nil
+}
+
 func WindCorrectionCmd(cmd CommandEntry, argv []string) error {
+	atFlag, argv, err := popFlagValue(argv, "--at")
+	if err != nil {
+		return err
+	}
 	var dist *float64
 	if len(argv) == 4 {
 		// Optional distance argument
@@ -57,38 +236,59 @@ func WindCorrectionCmd(cmd CommandEntry, argv []string) error {
 	} else if len(argv) != 3 {
 		return cmd.getUsageError()
 	}
+	var variation *float64
+	if atFlag != nil {
+		natfix, err := data.LoadNatfix()
+		if err != nil {
+			return err
+		}
+		pos, err := parse.ParsePos(natfix, *atFlag)
+		if err != nil {
+			return err
+		}
+		variation = loadVariationSource().Try(*atFlag, pos, 0)
+	}
 
 	// TODO: Consider swapping speed@dir to dir@speed
-	if wv, err := parse.ParseGeoVect(argv[2]); err != nil {
+	if wv, windNorth, err := parse.ParseWind(argv[2]); err != nil {
 		return err
 	} else if tas, err := strconv.ParseFloat(argv[0], 64); err != nil {
 		return err
-	} else if course, err := strconv.ParseFloat(argv[1], 64); err != nil {
+	} else if course, courseNorth, err := parse.ParseHeading(argv[1]); err != nil {
+		return err
+	} else if (courseNorth == geo.MagneticNorth || windNorth == geo.MagneticNorth) && variation == nil {
+		return errors.New("Magnetic directions require the magnetic variation, add --at POSITION")
+	} else if course, err = directionToTrue(course, courseNorth, variation, "course"); err != nil {
+		return err
+	} else if wv, err = windToTrue(wv, windNorth, variation); err != nil {
 		return err
 	} else {
-		return windCorrectionInternal(geo.Compass2Rad(course), tas, wv, dist)
+		return windCorrectionInternal(geo.Compass2Rad(course), tas, wv, dist, variation)
 	}
 }
 
-func windCorrectionInternal(course, tas float64, wind geo.Vect, dist *float64) error {
+// Directions are true, and also magnetic if variation (west positive) is not nil
+func windCorrectionInternal(course, tas float64, wind geo.Vect, dist, variation *float64) error {
 	// Situation
-	fmt.Printf("   Course:  %d\n", round(geo.Rad2Compass(course)))
+	fmt.Printf("   Course:  %s\n", formatDirection(geo.Rad2Compass(course), variation))
 	fmt.Printf("      TAS:  %d kts\n", round(tas))
-	fmt.Printf("     Wind:  %d kts @ %d\n",
+	fmt.Printf("     Wind:  %d kts @ %s\n",
 		round(wind.Magnitude()),
-		round(geo.Rad2Compass(wind.AsAngle())))
+		formatDirection(geo.Rad2Compass(wind.AsAngle()), variation))
 	if dist != nil {
 		fmt.Printf(" Distance:  %d NM\n", round(*dist))
 	}
+	if variation != nil {
+		fmt.Printf("  Mag Var:  %s\n", formatVariation(*variation))
+	}
 	fmt.Printf("\n")
 	// Results
-	h := heading(course, tas, wind)
-	gs := groundSpeed(course, h, tas, wind)
-	if math.IsNaN(h) || math.IsNaN(gs) {
-		return errors.New("Course is impossible to achieve under provided parameters")
+	h, gs, err := geo.WindTriangle(course, tas, wind)
+	if err != nil {
+		return err
 	}
 	fmt.Printf("      WCA:  %d\n", round(geo.Rad2Deg(course-h)))
-	fmt.Printf("  Heading:  %d\n", round(geo.Rad2Compass(h)))
+	fmt.Printf("  Heading:  %s\n", formatDirection(geo.Rad2Compass(h), variation))
 	fmt.Printf("Gnd speed:  %d kts\n", round(gs))
 	if dist != nil {
 		fmt.Printf("      ETE:  %.1f min\n", *dist/(gs/60))
@@ -0,0 +1,46 @@
+package cmds
+
+import (
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"github.com/cragcraig/flight/winds"
+	"testing"
+)
+
+// Winds from the west in the west and from the north in the east, so each leg
+// of a route across them gets a different wind
+func TestRouteWinds(t *testing.T) {
+	west := winds.Level{AltFt: 9000, Dir: 270, Speed: 30}
+	north := winds.Level{AltFt: 9000, Dir: 360, Speed: 10}
+	forecast := winds.Forecast{Stations: map[string]winds.Station{
+		"WST": winds.Station{Id: "WST", Coord: geo.NewCoord(40, -106), Levels: []winds.Level{west}},
+		"EST": winds.Station{Id: "EST", Coord: geo.NewCoord(40, -100), Levels: []winds.Level{north}},
+	}}
+	route := parse.Route{
+		{Name: "A", Pos: geo.NewCoord(40, -106.1)},
+		{Name: "B", Pos: geo.NewCoord(40, -105.9)},
+		{Name: "C", Pos: geo.NewCoord(40, -100.1)},
+		{Name: "D", Pos: geo.NewCoord(40, -99.9)},
+	}
+	got, err := routeWinds(forecast, route, 9000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Got %d winds, want one per leg", len(got))
+	}
+	// The first and last legs are centered on a station
+	if got[0].DistanceTo(west.Wind()) > 1e-9 || got[2].DistanceTo(north.Wind()) > 1e-9 {
+		t.Errorf("Got %+v and %+v, want %+v and %+v", got[0], got[2], west.Wind(), north.Wind())
+	}
+	// And the middle leg between them
+	if mid, err := forecast.At(geo.IntermediatePoint(route[1].Pos, route[2].Pos, 0.5), 9000); err != nil {
+		t.Fatal(err)
+	} else if got[1].DistanceTo(mid.Wind) > 1e-9 {
+		t.Errorf("Got %+v, want %+v", got[1], mid.Wind)
+	}
+
+	if _, err := routeWinds(winds.Forecast{Stations: map[string]winds.Station{}}, route, 9000); err == nil {
+		t.Error("Expected an error without a forecast")
+	}
+}
@@ -0,0 +1,306 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 56 Day NASR Subscription AWY.txt
+// https://www.faa.gov/air_traffic/flight_info/aeronav/Aero_Data/
+//
+// Only the AWY1 segment and AWY2 point records are used. Create truncated
+// database from the full datafile:
+// $ egrep '^AWY[12]' AWY.txt > AWY-trunc.txt
+type Airways struct {
+	data map[string][]awyEntry // Alaska and Hawaii airways reuse designations
+}
+
+type awyEntry struct {
+	id     string
+	region string
+	points []awyPointEntry // In sequence order
+}
+
+type awyPointEntry struct {
+	seq int
+	// AWY2 point record
+	name, kind, lat, lon, navaid string
+	// AWY1 segment record, for the segment to the following point
+	dist, mea, meaOpposite, moca, gap, changeover string
+}
+
+// Public type, parsed from awyEntry on lookup
+type Airway struct {
+	Id     string // e.g., V8 or J52
+	Region string // Empty for the contiguous US, A for Alaska or H for Hawaii
+	Points []AirwayPoint
+}
+
+type AirwayPoint struct {
+	Id    string // Navaid identifier, otherwise the fix name
+	Type  string // e.g., VORTAC or REP-PT
+	Coord geo.Coord
+	// Segment to the following point, nil for the last point
+	Next *AirwaySegment
+}
+
+type AirwaySegment struct {
+	DistNM       float64
+	Mea          int     // Zero if not published
+	MeaOpposite  int     // Flown toward the earlier point, zero if the same as Mea
+	Moca         int     // Zero if not published
+	ChangeoverNM float64 // From the start of the segment, zero if at the midpoint
+	Gap          bool    // The airway isn't continuous to the following point
+}
+
+// A segment between consecutive airway points, in the direction flown
+type AirwayLeg struct {
+	From, To AirwayPoint
+	Segment  AirwaySegment // Mea is the MEA in this direction
+}
+
+func LoadAirways() (Airways, error) {
+	// Files to be attempted, in order
+	fnames := []string{"AWY-trunc.txt", "AWY.txt"}
+	errs := []string{}
+	for _, fname := range fnames {
+		if file, err := os.Open(fname); err == nil {
+			defer file.Close()
+			return parseAwy(file)
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+	return Airways{}, errors.New(strings.Join(errs, "\n"))
+}
+
+func (a Airways) IsAirway(id string) bool {
+	_, exists := a.data[strings.ToUpper(id)]
+	return exists
+}
+
+// Every airway with the designation
+func (a Airways) Get(id string) ([]Airway, error) {
+	entries, exists := a.data[strings.ToUpper(id)]
+	if !exists {
+		return nil, errors.New("Not found in AWY database: " + id)
+	}
+	airways := []Airway{}
+	for _, e := range entries {
+		if awy, err := e.parse(); err != nil {
+			return nil, err
+		} else {
+			airways = append(airways, awy)
+		}
+	}
+	return airways, nil
+}
+
+// Segments flown along an airway from the entry to the exit point, which are
+// navaid identifiers or fix names
+func (a Airways) Expand(id, entry, exit string) ([]AirwayLeg, error) {
+	airways, err := a.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, awy := range airways {
+		if from, to := awy.index(entry), awy.index(exit); from >= 0 && to >= 0 {
+			return awy.legs(from, to)
+		}
+	}
+	return nil, fmt.Errorf("%s and %s are not both on airway %s", strings.ToUpper(entry), strings.ToUpper(exit), strings.ToUpper(id))
+}
+
+// Index of the point, or -1 if not on the airway
+func (awy Airway) index(id string) int {
+	for i, p := range awy.Points {
+		if strings.EqualFold(p.Id, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (awy Airway) legs(from, to int) ([]AirwayLeg, error) {
+	if from == to {
+		return nil, fmt.Errorf("Airway %s entered and exited at %s", awy.Id, awy.Points[from].Id)
+	}
+	step := 1
+	if to < from {
+		step = -1
+	}
+	legs := []AirwayLeg{}
+	for i := from; i != to; i += step {
+		// Segments are described from the earlier point
+		first := i
+		if step < 0 {
+			first = i - 1
+		}
+		seg := *awy.Points[first].Next
+		if seg.Gap {
+			return nil, fmt.Errorf("Airway %s has a gap between %s and %s", awy.Id, awy.Points[first].Id, awy.Points[first+1].Id)
+		}
+		if step < 0 {
+			if seg.MeaOpposite != 0 {
+				seg.Mea, seg.MeaOpposite = seg.MeaOpposite, seg.Mea
+			}
+			if seg.ChangeoverNM != 0 {
+				seg.ChangeoverNM = seg.DistNM - seg.ChangeoverNM
+			}
+		}
+		legs = append(legs, AirwayLeg{From: awy.Points[i], To: awy.Points[i+step], Segment: seg})
+	}
+	return legs, nil
+}
+
+func (e awyEntry) parse() (Airway, error) {
+	awy := Airway{Id: e.id, Region: e.region}
+	for i, p := range e.points {
+		lat, err := parseAwyLatOrLon(p.lat)
+		if err != nil {
+			return Airway{}, fmt.Errorf("Error parsing airway %s: %s", e.id, err)
+		}
+		lon, err := parseAwyLatOrLon(p.lon)
+		if err != nil {
+			return Airway{}, fmt.Errorf("Error parsing airway %s: %s", e.id, err)
+		}
+		point := AirwayPoint{Id: p.navaid, Type: p.kind, Coord: geo.NewCoord(lat, lon)}
+		if len(point.Id) == 0 {
+			point.Id = p.name
+		}
+		if i != len(e.points)-1 {
+			seg, err := p.parseSegment()
+			if err != nil {
+				return Airway{}, fmt.Errorf("Error parsing airway %s at %s: %s", e.id, point.Id, err)
+			}
+			point.Next = &seg
+		}
+		awy.Points = append(awy.Points, point)
+	}
+	// Distances aren't published for every segment
+	for i, p := range awy.Points {
+		if p.Next != nil && p.Next.DistNM == 0 {
+			p.Next.DistNM = geo.DistanceNM(p.Coord, awy.Points[i+1].Coord)
+		}
+	}
+	return awy, nil
+}
+
+func (p awyPointEntry) parseSegment() (AirwaySegment, error) {
+	seg := AirwaySegment{Gap: len(p.gap) != 0}
+	var err error
+	if seg.DistNM, err = optionalFloat(p.dist); err != nil {
+		return AirwaySegment{}, errors.New("Invalid distance: " + p.dist)
+	} else if seg.ChangeoverNM, err = optionalFloat(p.changeover); err != nil {
+		return AirwaySegment{}, errors.New("Invalid changeover point distance: " + p.changeover)
+	} else if seg.Mea, err = optionalInt(p.mea); err != nil {
+		return AirwaySegment{}, errors.New("Invalid MEA: " + p.mea)
+	} else if seg.MeaOpposite, err = optionalInt(p.meaOpposite); err != nil {
+		return AirwaySegment{}, errors.New("Invalid MEA: " + p.meaOpposite)
+	} else if seg.Moca, err = optionalInt(p.moca); err != nil {
+		return AirwaySegment{}, errors.New("Invalid MOCA: " + p.moca)
+	}
+	return seg, nil
+}
+
+func optionalFloat(s string) (float64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// e.g., 39-54-47.000N or 105-08-20.000W
+func parseAwyLatOrLon(s string) (float64, error) {
+	e := errors.New("Invalid Lon/Lat: " + s)
+	if len(s) < 2 {
+		return math.NaN(), e
+	}
+	parts := strings.Split(s[:len(s)-1], "-")
+	if len(parts) != 3 {
+		return math.NaN(), e
+	}
+	v := 0.0
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return math.NaN(), e
+		}
+		v += f / math.Pow(60, float64(i))
+	}
+	switch s[len(s)-1] {
+	case 'N', 'E':
+		return v, nil
+	case 'S', 'W':
+		return -v, nil
+	}
+	return math.NaN(), e
+}
+
+func parseAwy(r io.Reader) (Airways, error) {
+	// Points by airway designation and region, then sequence number
+	points := make(map[[2]string]map[int]awyPointEntry)
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		l := s.Text()
+		record := getField(l, 1, 4)
+		if record != "AWY1" && record != "AWY2" {
+			// Changeover points and remarks
+			continue
+		}
+		key := [2]string{getField(l, 5, 5), getField(l, 10, 1)}
+		seq, err := strconv.Atoi(getField(l, 11, 5))
+		if err != nil {
+			return Airways{}, fmt.Errorf("Error parsing AWY: Invalid sequence number for %s: %s", key[0], getField(l, 11, 5))
+		}
+		if points[key] == nil {
+			points[key] = make(map[int]awyPointEntry)
+		}
+		p := points[key][seq]
+		p.seq = seq
+		if record == "AWY1" {
+			p.dist = getField(l, 69, 6)
+			if len(p.dist) == 0 {
+				p.dist = getField(l, 45, 6)
+			}
+			p.mea = getField(l, 75, 5)
+			p.meaOpposite = getField(l, 86, 5)
+			p.moca = getField(l, 102, 5)
+			p.gap = getField(l, 107, 1)
+			p.changeover = getField(l, 108, 3)
+		} else {
+			p.name = getField(l, 16, 30)
+			p.kind = getField(l, 46, 19)
+			p.lat = getField(l, 84, 14)
+			p.lon = getField(l, 98, 14)
+			p.navaid = getField(l, 117, 4)
+		}
+		points[key][seq] = p
+	}
+	if err := s.Err(); err != nil {
+		return Airways{}, errors.New("Error parsing AWY: " + err.Error())
+	}
+	airways := Airways{
+		data: make(map[string][]awyEntry),
+	}
+	for key, bySeq := range points {
+		e := awyEntry{id: key[0], region: key[1]}
+		for _, p := range bySeq {
+			e.points = append(e.points, p)
+		}
+		sort.Slice(e.points, func(i, j int) bool {
+			return e.points[i].seq < e.points[j].seq
+		})
+		airways.data[e.id] = append(airways.data[e.id], e)
+	}
+	return airways, nil
+}
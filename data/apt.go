@@ -3,10 +3,12 @@ package data
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"github.com/cragcraig/flight/geo"
 	"io"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -14,27 +16,69 @@ import (
 // 56 Day NASR Subscription APT.txt
 // https://www.faa.gov/air_traffic/flight_info/aeronav/Aero_Data/
 //
-// Note: The file under source control is truncated to only AIRPORT entries
-// and only includes airports with a 3 digit FAA location identifier.
+// Note: The file under source control is truncated to only landing facility
+// entries, with their runway, attendance, arresting system and remark records.
 // Create truncated database from the full datafile:
-// $ egrep '^\S+\s*AIRPORT\s*\w{3}\W' APT.txt > APT-trunc.txt
+// $ egrep '^(APT|RWY|ATT|ARS|RMK)' APT.txt > APT-trunc.txt
 type Apts struct {
-	data map[string]aptEntry
+	data map[string]aptEntry // By FAA LID
+	icao map[string]string   // FAA LID by ICAO code
 }
 
 type aptEntry struct {
 	id        string
+	kind      string
 	lat, lon  string
 	alt       string
 	variation string
+	name      string
+	city      string
+	state     string
+	status    string
+	tpa       string
+	fuel      string
+	unicom    string
+	ctaf      string
+	icao      string
+	// Records following the APT record for the same site
+	runways    []rwyEntry
+	attendance []string
+	arresting  []ArrestingSystem
+	remarks    []Remark
 }
 
 // Public type, parsed from aptEntry on lookup
 type Apt struct {
-	Id        string
-	Coord     geo.Coord
-	Alt       int
-	Variation int
+	Id         string // ICAO code if assigned, otherwise the FAA LID
+	Lid        string
+	Type       FacilityType
+	Coord      geo.Coord
+	Alt        int
+	Variation  *int // West positive, nil if not published
+	Name       string
+	City       string
+	State      string // Post office code, e.g., CO
+	Status     string // O operational, CI closed indefinitely, CP closed permanently
+	PatternAgl int    // Traffic pattern altitude above the airport, zero if not published
+	Ctaf       string // Frequencies in MHz, empty if none
+	Unicom     string
+	FuelTypes  []string
+	Icao       string
+	Runways    []Runway
+	Attendance []string // e.g., ALL/ALL/0700-1900
+	Arresting  []ArrestingSystem
+	Remarks    []Remark
+}
+
+type ArrestingSystem struct {
+	Runway string
+	End    string
+	Type   string // e.g., BAK-12
+}
+
+type Remark struct {
+	Element string // Field or record the remark refers to, e.g., A81
+	Text    string
 }
 
 func LoadApts() (Apts, error) {
@@ -52,40 +96,136 @@ func LoadApts() (Apts, error) {
 	return Apts{}, errors.New(strings.Join(errs, "\n"))
 }
 
+// Landing facility by FAA LID or ICAO code, see Resolve
 func (a Apts) GetApt(station string) (Apt, error) {
-	if len(station) == 0 {
-		return Apt{}, errors.New("Invalid aiport identifier: empty string")
-	}
-	if v, exists := a.data[strings.ToUpper(station[1:len(station)])]; !exists {
-		return Apt{}, errors.New("Not found in APT database: " + station)
+	if ident, err := a.Resolve(station); err != nil {
+		return Apt{}, err
 	} else {
-		lat, err := parseAptLatOrLon(v.lat)
-		if err != nil {
-			return Apt{}, err
+		return a.data[ident.Lid].parse()
+	}
+}
+
+// Landing facility closest to c, of any type
+func (a Apts) Nearest(c geo.Coord) (Apt, error) {
+	all, err := a.All()
+	if err != nil {
+		return Apt{}, err
+	}
+	var nearest Apt
+	min := math.Inf(1)
+	for _, apt := range all {
+		if d := geo.GlobeDistNM(c, apt.Coord); d < min {
+			nearest, min = apt, d
 		}
-		lon, err := parseAptLatOrLon(v.lon)
-		if err != nil {
-			return Apt{}, err
+	}
+	return nearest, nil
+}
+
+// Up to n landing facilities of the given types (any if empty) closest to c,
+// nearest first
+func (a Apts) NearestN(c geo.Coord, n int, types []FacilityType) ([]Apt, error) {
+	all, err := a.All()
+	if err != nil {
+		return nil, err
+	}
+	matches := []Apt{}
+	for _, apt := range all {
+		if apt.IsType(types) {
+			matches = append(matches, apt)
 		}
-		alt, err := parseAptAlt(v.alt)
-		if err != nil {
-			return Apt{}, err
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return geo.GlobeDistNM(c, matches[i].Coord) < geo.GlobeDistNM(c, matches[j].Coord)
+	})
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches, nil
+}
+
+// Every landing facility in the database, in no particular order. Records
+// that fail to parse are skipped, so one bad record doesn't hide the rest.
+func (a Apts) All() ([]Apt, error) {
+	apts := make([]Apt, 0, len(a.data))
+	var lastErr error
+	for _, v := range a.data {
+		if apt, err := v.parse(); err != nil {
+			lastErr = err
+		} else {
+			apts = append(apts, apt)
+		}
+	}
+	if len(apts) == 0 && lastErr != nil {
+		return nil, lastErr
+	} else if len(apts) == 0 {
+		return nil, errors.New("APT database is empty")
+	}
+	return apts, nil
+}
+
+func (v aptEntry) parse() (Apt, error) {
+	lat, err := parseAptLatOrLon(v.lat)
+	if err != nil {
+		return Apt{}, err
+	}
+	lon, err := parseAptLatOrLon(v.lon)
+	if err != nil {
+		return Apt{}, err
+	}
+	apt := Apt{
+		Id:         Ident{Lid: v.id, Icao: v.icao}.Preferred(),
+		Lid:        v.id,
+		Type:       FacilityType(v.kind),
+		Coord:      geo.NewCoord(lat, lon),
+		Name:       v.name,
+		City:       v.city,
+		State:      v.state,
+		Status:     v.status,
+		Ctaf:       v.ctaf,
+		Unicom:     v.unicom,
+		FuelTypes:  strings.Fields(v.fuel),
+		Icao:       v.icao,
+		Attendance: v.attendance,
+		Arresting:  v.arresting,
+		Remarks:    v.remarks,
+	}
+	// Elevation and variation aren't published for every facility
+	if len(v.alt) != 0 {
+		if apt.Alt, err = parseAptAlt(v.alt); err != nil {
+			return Apt{}, fmt.Errorf("Error parsing Apt %s: %s", apt.Id, err)
 		}
+	}
+	if len(v.variation) != 0 {
 		variation, err := parseAptVariation(v.variation)
 		if err != nil {
-			return Apt{}, err
+			return Apt{}, fmt.Errorf("Error parsing Apt %s: %s", apt.Id, err)
 		}
-		return Apt{
-			Id:        "K" + v.id,
-			Coord:     geo.NewCoord(lat, lon),
-			Alt:       alt,
-			Variation: variation,
-		}, nil
+		apt.Variation = &variation
 	}
+	if len(v.tpa) != 0 {
+		if apt.PatternAgl, err = strconv.Atoi(v.tpa); err != nil {
+			return Apt{}, errors.New("Error parsing Apt: Invalid traffic pattern altitude: " + v.tpa)
+		}
+	}
+	for _, r := range v.runways {
+		rwy, err := r.parse()
+		if err != nil {
+			return Apt{}, fmt.Errorf("Error parsing Apt %s: %s", apt.Id, err)
+		}
+		apt.Runways = append(apt.Runways, rwy)
+	}
+	return apt, nil
+}
+
+func (apt Apt) Ident() Ident {
+	return Ident{Lid: apt.Lid, Icao: apt.Icao}
 }
 
 func parseAptLatOrLon(s string) (float64, error) {
 	e := errors.New("Error parsing Apt: Invalid Lon/Lat: " + s)
+	if len(s) < 2 {
+		return math.NaN(), e
+	}
 	l, err := strconv.ParseFloat(s[:len(s)-1], 64)
 	if err != nil {
 		return math.NaN(), e
@@ -109,6 +249,9 @@ func parseAptAlt(alt string) (int, error) {
 }
 
 func parseAptVariation(v string) (int, error) {
+	if len(v) < 2 {
+		return -1, errors.New("Invalid declination for variation: " + v)
+	}
 	i, err := strconv.Atoi(v[:len(v)-1])
 	if err != nil {
 		return -1, err
@@ -125,21 +268,53 @@ func parseAptVariation(v string) (int, error) {
 func parseApt(r io.Reader) (Apts, error) {
 	apts := Apts{
 		data: make(map[string]aptEntry),
+		icao: make(map[string]string),
 	}
+	// Location identifier for each site number, which links the records
+	// following an APT record to it
+	sites := make(map[string]string)
 	// Parse station lines
 	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for s.Scan() {
 		l := s.Text()
-		// TODO: Also allow types:
-		// BALLOONPORT, SEAPLANE BASE, GLIDERPORT, HELIPORT, ULTRALIGHT
-		if getField(l, 1, 3) != "APT" || getField(l, 15, 13) != "AIRPORT" {
+		record := getField(l, 1, 3)
+		if record == "APT" {
+			if e, err := parseAptEntry(l); err != nil {
+				return Apts{}, err
+			} else {
+				apts.data[e.id] = e
+				if len(e.icao) != 0 {
+					apts.icao[e.icao] = e.id
+				}
+				sites[getField(l, 4, 11)] = e.id
+			}
 			continue
 		}
-		if e, err := parseAptEntry(l); err != nil {
-			return Apts{}, err
-		} else {
-			apts.data[e.id] = e
+		id, exists := sites[getField(l, 4, 11)]
+		if !exists {
+			// Unknown record
+			continue
 		}
+		e := apts.data[id]
+		switch record {
+		case "RWY":
+			e.runways = append(e.runways, parseRwyEntry(l))
+		case "ATT":
+			e.attendance = append(e.attendance, getField(l, 19, 108))
+		case "ARS":
+			e.arresting = append(e.arresting, ArrestingSystem{
+				Runway: getField(l, 17, 7),
+				End:    getField(l, 24, 3),
+				Type:   getField(l, 27, 9),
+			})
+		case "RMK":
+			e.remarks = append(e.remarks, Remark{
+				Element: getField(l, 17, 13),
+				Text:    getField(l, 30, 1500),
+			})
+		}
+		apts.data[id] = e
 	}
 	if err := s.Err(); err != nil {
 		return Apts{}, errors.New("Error parsing APT: " + err.Error())
@@ -148,16 +323,34 @@ func parseApt(r io.Reader) (Apts, error) {
 }
 
 // The layout data is described using 1-based indexes, so follow that convention here.
+// Trailing fields may be missing from lines with trailing whitespace removed.
 func getField(line string, start, length uint) string {
-	return strings.TrimSpace(line[start-1 : start+length-1])
+	if int(start) > len(line) {
+		return ""
+	}
+	end := start + length - 1
+	if int(end) > len(line) {
+		end = uint(len(line))
+	}
+	return strings.TrimSpace(line[start-1 : end])
 }
 
 func parseAptEntry(l string) (aptEntry, error) {
 	return aptEntry{
 		id:        getField(l, 28, 4),
+		kind:      getField(l, 15, 13),
+		state:     getField(l, 49, 2),
+		city:      getField(l, 94, 40),
+		name:      getField(l, 134, 50),
 		lat:       getField(l, 539, 12),
 		lon:       getField(l, 566, 12),
 		alt:       getField(l, 579, 7),
 		variation: getField(l, 587, 3),
+		tpa:       getField(l, 594, 4),
+		status:    getField(l, 815, 2),
+		fuel:      getField(l, 875, 40),
+		unicom:    getField(l, 956, 7),
+		ctaf:      getField(l, 963, 7),
+		icao:      getField(l, 1211, 7),
 	}, nil
 }
@@ -0,0 +1,72 @@
+package data
+
+import (
+	"errors"
+	"strings"
+)
+
+// Landing facility type, as named in APT.txt
+type FacilityType string
+
+const (
+	Airport      FacilityType = "AIRPORT"
+	Balloonport  FacilityType = "BALLOONPORT"
+	SeaplaneBase FacilityType = "SEAPLANE BASE"
+	Gliderport   FacilityType = "GLIDERPORT"
+	Heliport     FacilityType = "HELIPORT"
+	Ultralight   FacilityType = "ULTRALIGHT"
+)
+
+var FacilityTypes = []FacilityType{Airport, Balloonport, SeaplaneBase, Gliderport, Heliport, Ultralight}
+
+// e.g., seaplane base
+func (f FacilityType) String() string {
+	return strings.ToLower(string(f))
+}
+
+// Accepts the type name or an unambiguous prefix, e.g., seaplane or glider
+func ParseFacilityType(s string) (FacilityType, error) {
+	name := strings.ToUpper(strings.TrimSpace(strings.Replace(strings.Replace(s, "-", " ", -1), "_", " ", -1)))
+	matches := []FacilityType{}
+	for _, f := range FacilityTypes {
+		if name == string(f) {
+			return f, nil
+		} else if len(name) != 0 && strings.HasPrefix(string(f), name) {
+			matches = append(matches, f)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	names := []string{}
+	for _, f := range FacilityTypes {
+		names = append(names, f.String())
+	}
+	return "", errors.New("Unknown facility type, expected one of " + strings.Join(names, ", ") + ": " + s)
+}
+
+// Comma separated facility types, e.g., airport,glider
+func ParseFacilityTypes(s string) ([]FacilityType, error) {
+	types := []FacilityType{}
+	for _, t := range strings.Split(s, ",") {
+		f, err := ParseFacilityType(t)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, f)
+	}
+	return types, nil
+}
+
+// True if types is empty or contains the facility's type
+func (a Apt) IsType(types []FacilityType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if a.Type == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"errors"
+	"strings"
+)
+
+// A landing facility is identified by its FAA location identifier (LID),
+// e.g., BDU, 00C or CO12, and optionally an ICAO code, e.g., KBDU or PANC.
+// ICAO codes aren't always the LID prefixed by K, so they're mapped using the
+// ICAO field of the APT record.
+type Ident struct {
+	Lid  string
+	Icao string // Empty if none is assigned
+}
+
+// The identifier to prefer, ICAO if assigned
+func (i Ident) Preferred() string {
+	if len(i.Icao) != 0 {
+		return i.Icao
+	}
+	return i.Lid
+}
+
+// e.g., KBDU/BDU, or 00C when there's no ICAO code
+func (i Ident) String() string {
+	if len(i.Icao) == 0 || i.Icao == i.Lid {
+		return i.Lid
+	}
+	return i.Icao + "/" + i.Lid
+}
+
+// Both identifiers of a landing facility given either, case insensitive
+func (a Apts) Resolve(station string) (Ident, error) {
+	if len(station) == 0 {
+		return Ident{}, errors.New("Invalid airport identifier: empty string")
+	}
+	id := strings.ToUpper(station)
+	if v, exists := a.data[id]; exists {
+		return Ident{Lid: v.id, Icao: v.icao}, nil
+	} else if lid, exists := a.icao[id]; exists {
+		return Ident{Lid: lid, Icao: id}, nil
+	}
+	return Ident{}, errors.New("Not found in APT database: " + station)
+}
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"strings"
+)
+
+var morseCode = map[rune]string{
+	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".", 'F': "..-.",
+	'G': "--.", 'H': "....", 'I': "..", 'J': ".---", 'K': "-.-", 'L': ".-..",
+	'M': "--", 'N': "-.", 'O': "---", 'P': ".--.", 'Q': "--.-", 'R': ".-.",
+	'S': "...", 'T': "-", 'U': "..-", 'V': "...-", 'W': ".--", 'X': "-..-",
+	'Y': "-.--", 'Z': "--..",
+	'0': "-----", '1': ".----", '2': "..---", '3': "...--", '4': "....-",
+	'5': ".....", '6': "-....", '7': "--...", '8': "---..", '9': "----.",
+}
+
+// Morse code of an identifier, letters separated by spaces, e.g., -... .--- -.-.
+func Morse(id string) string {
+	letters := []string{}
+	for _, r := range strings.ToUpper(id) {
+		if m, exists := morseCode[r]; exists {
+			letters = append(letters, m)
+		}
+	}
+	return strings.Join(letters, " ")
+}
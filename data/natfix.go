@@ -3,6 +3,7 @@ package data
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"github.com/cragcraig/flight/geo"
 	"io"
 	"math"
@@ -23,6 +24,9 @@ type natfixEntry struct {
 	lon, lat     string
 	region       string
 	station_type string
+	// Parsed once on load, see GetFix
+	pos    geo.Coord
+	posErr error
 }
 
 func LoadNatfix() (Natfix, error) {
@@ -31,7 +35,7 @@ func LoadNatfix() (Natfix, error) {
 		return Natfix{}, errors.New("Unable to load NATFIX database: " + err.Error())
 	}
 	defer file.Close()
-	return parseNatfix(file)
+	return ParseNatfix(file)
 }
 
 // TODO: Should this return a time.Time?
@@ -42,17 +46,41 @@ func (n Natfix) Issued() string {
 func (n Natfix) GetFix(station string) (geo.Coord, error) {
 	if v, exists := n.data[strings.ToUpper(station)]; !exists {
 		return geo.ErrCoord(), errors.New("Not found in NATFIX database: " + station)
+	} else if v.posErr != nil {
+		return geo.ErrCoord(), v.posErr
 	} else {
-		lat, err := parseLat(v.lat)
-		if err != nil {
-			return geo.ErrCoord(), err
+		return v.pos, nil
+	}
+}
+
+// Closest fix to c no further than maxNM away
+func (n Natfix) Nearest(c geo.Coord, maxNM float64) (string, geo.Coord, error) {
+	return n.NearestWhere(c, maxNM, func(_ string, _ geo.Coord) bool { return true })
+}
+
+// As Nearest, considering only fixes for which accept is true. Fixes with an
+// unparseable position are skipped, and ties go to the lowest id.
+func (n Natfix) NearestWhere(c geo.Coord, maxNM float64, accept func(string, geo.Coord) bool) (string, geo.Coord, error) {
+	best, bestPos, bestDist := "", geo.ErrCoord(), maxNM
+	for id, v := range n.data {
+		if v.posErr != nil {
+			continue
 		}
-		lon, err := parseLon(v.lon)
-		if err != nil {
-			return geo.ErrCoord(), err
+		d := geo.GlobeDistNM(c, v.pos)
+		if d > bestDist || (d == bestDist && best != "" && id > best) || !accept(id, v.pos) {
+			continue
 		}
-		return geo.NewCoord(lat, lon), nil
+		best, bestPos, bestDist = id, v.pos, d
+	}
+	if best == "" {
+		return "", geo.ErrCoord(), fmt.Errorf("No NATFIX fix within %.1f NM of %s", maxNM, c)
 	}
+	return best, bestPos, nil
+}
+
+// e.g., VORTAC, NDB, REP-PT or ARPT, empty if not found
+func (n Natfix) StationType(station string) string {
+	return n.data[strings.ToUpper(station)].station_type
 }
 
 func parseLon(lon string) (float64, error) {
@@ -99,7 +127,8 @@ func parseLat(lat string) (float64, error) {
 	}
 }
 
-func parseNatfix(r io.Reader) (Natfix, error) {
+// Parses a database in the NATFIX.txt format
+func ParseNatfix(r io.Reader) (Natfix, error) {
 	s := bufio.NewScanner(r)
 	// First line should be "NATFIX"
 	s.Scan()
@@ -137,11 +166,21 @@ func parseNatfixEntry(entry string) (natfixEntry, error) {
 	if len(fields) < 7 || fields[0] != "I" {
 		return natfixEntry{}, errors.New("Invalid NATFIX entry: " + entry)
 	}
-	return natfixEntry{
+	e := natfixEntry{
 		id:           getField(entry, 3, 5),
 		lat:          getField(entry, 9, 7),
 		lon:          getField(entry, 17, 8),
 		region:       getField(entry, 35, 2),
 		station_type: getField(entry, 38, 7),
-	}, nil
+	}
+	lat, errLat := parseLat(e.lat)
+	lon, errLon := parseLon(e.lon)
+	if errLat != nil {
+		e.posErr = errLat
+	} else if errLon != nil {
+		e.posErr = errLon
+	} else {
+		e.pos = geo.NewCoord(lat, lon)
+	}
+	return e, nil
 }
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"strings"
+	"testing"
+)
+
+func natfixLine(id, lat, lon, stationType string) string {
+	return fmt.Sprintf("I %-5s %s %s 'ZDV  CO    %-7s", id, lat, lon, stationType)
+}
+
+func testNatfix(t *testing.T, lines ...string) Natfix {
+	text := "NATFIX\n'20261018\n" + strings.Join(lines, "\n") + "\n$\n"
+	n, err := ParseNatfix(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestGetFix(t *testing.T) {
+	n := testNatfix(t,
+		natfixLine("BJC", "395449N", "1050820W", "VORTAC"),
+		natfixLine("BAD", "3954X9N", "1050820W", "REP-PT"))
+	if c, err := n.GetFix("bjc"); err != nil {
+		t.Fatal(err)
+	} else if c.String() != "39.9136,-105.1389" {
+		t.Errorf("BJC: got %s", c)
+	}
+	if _, err := n.GetFix("BAD"); err == nil {
+		t.Error("Expected an error for an unparseable position")
+	}
+	if _, err := n.GetFix("NONE"); err == nil {
+		t.Error("Expected an error for a missing fix")
+	}
+	if n.StationType("BJC") != "VORTAC" || n.StationType("NONE") != "" {
+		t.Errorf("Station type: got %q, %q", n.StationType("BJC"), n.StationType("NONE"))
+	}
+}
+
+func TestNearestWhere(t *testing.T) {
+	n := testNatfix(t,
+		// Equidistant east and west of 40N 105W
+		natfixLine("WEST", "400000N", "1050600W", "REP-PT"),
+		natfixLine("EAST", "400000N", "1045400W", "REP-PT"),
+		natfixLine("BAD", "4000X0N", "1050000W", "REP-PT"),
+		natfixLine("FAR", "410000N", "1050000W", "VORTAC"))
+	c := geo.NewCoord(40, -105)
+	// The unparseable record is skipped, and the tie goes to the lower id
+	for i := 0; i < 10; i++ {
+		if id, _, err := n.Nearest(c, 10); err != nil {
+			t.Fatal(err)
+		} else if id != "EAST" {
+			t.Fatalf("Nearest: got %s, want EAST", id)
+		}
+	}
+	onlyWest := func(id string, _ geo.Coord) bool { return id != "EAST" }
+	if id, _, err := n.NearestWhere(c, 10, onlyWest); err != nil || id != "WEST" {
+		t.Errorf("NearestWhere: got %s, %v, want WEST", id, err)
+	}
+	vor := func(id string, _ geo.Coord) bool { return n.StationType(id) == "VORTAC" }
+	if id, _, err := n.NearestWhere(c, 10, vor); err == nil {
+		t.Errorf("NearestWhere: got %s, want none within 10 NM", id)
+	}
+}
@@ -0,0 +1,256 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 56 Day NASR Subscription NAV.txt
+// https://www.faa.gov/air_traffic/flight_info/aeronav/Aero_Data/
+//
+// Only the NAV1 base records are used. Create truncated database from the
+// full datafile:
+// $ grep '^NAV1' NAV.txt > NAV-trunc.txt
+type Navaids struct {
+	data map[string][]navEntry // Identifiers aren't unique, e.g., an NDB and VOR
+}
+
+type navEntry struct {
+	id            string
+	kind          string
+	name          string
+	city          string
+	state         string
+	class         string
+	lat, lon      string
+	elevation     string
+	variation     string
+	variationYear string
+	channel       string
+	frequency     string
+	vorVolume     string
+	dmeVolume     string
+	status        string
+}
+
+// Public type, parsed from navEntry on lookup
+type Navaid struct {
+	Id            string
+	Type          string // e.g., VORTAC, VOR/DME or NDB
+	Name          string
+	City          string
+	State         string
+	Class         string // e.g., H-VORTAC
+	Coord         geo.Coord
+	Elevation     int
+	Variation     int     // Slaved variation the radials are aligned to, west positive
+	VariationYear int     // Zero if not published
+	Channel       string  // TACAN or DME channel, e.g., 101X
+	Frequency     float64 // MHz, or kHz for NDBs
+	ServiceVolume ServiceVolume
+	Status        string // e.g., OPERATIONAL IFR
+}
+
+func LoadNavaids() (Navaids, error) {
+	// Files to be attempted, in order
+	fnames := []string{"NAV-trunc.txt", "NAV.txt"}
+	errs := []string{}
+	for _, fname := range fnames {
+		if file, err := os.Open(fname); err == nil {
+			defer file.Close()
+			return parseNav(file)
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+	return Navaids{}, errors.New(strings.Join(errs, "\n"))
+}
+
+// Every navaid with the identifier
+func (n Navaids) Get(id string) ([]Navaid, error) {
+	entries, exists := n.data[strings.ToUpper(id)]
+	if !exists {
+		return nil, errors.New("Not found in NAV database: " + id)
+	}
+	navaids := []Navaid{}
+	for _, v := range entries {
+		if nav, err := v.parse(); err != nil {
+			return nil, err
+		} else {
+			navaids = append(navaids, nav)
+		}
+	}
+	return navaids, nil
+}
+
+// Operational VORs with service volumes covering the whole leg from start to
+// end at altFt MSL, nearest to the leg first. Unparseable records are skipped.
+func (n Navaids) Covering(start, end geo.Coord, altFt float64) ([]Navaid, error) {
+	points, err := geo.GreatCirclePoints(start, end, coverage_spacing_nm)
+	if err != nil {
+		return nil, err
+	}
+	covering := []Navaid{}
+	furthest := []float64{}
+	for _, entries := range n.data {
+		for _, v := range entries {
+			if !strings.HasPrefix(v.kind, "VOR") {
+				continue
+			}
+			nav, err := v.parse()
+			if err != nil || !nav.IsOperational() {
+				continue
+			}
+			if max := maxDistNM(nav.Coord, points); max <= nav.ServiceVolume.RangeNM(altFt-float64(nav.Elevation)) {
+				covering = append(covering, nav)
+				furthest = append(furthest, max)
+			}
+		}
+	}
+	sort.Sort(byDist{covering, furthest})
+	return covering, nil
+}
+
+// Spacing of the points along a leg checked to be within a service volume
+const coverage_spacing_nm = 2
+
+// Greatest distance from the navaid to the leg from start to end
+func (nav Navaid) MaxDistNM(start, end geo.Coord) (float64, error) {
+	points, err := geo.GreatCirclePoints(start, end, coverage_spacing_nm)
+	if err != nil {
+		return 0, err
+	}
+	return maxDistNM(nav.Coord, points), nil
+}
+
+func maxDistNM(c geo.Coord, points []geo.Coord) float64 {
+	max := 0.0
+	for _, p := range points {
+		if d := geo.GlobeDistNM(c, p); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+type byDist struct {
+	navaids []Navaid
+	dists   []float64
+}
+
+func (b byDist) Len() int           { return len(b.navaids) }
+func (b byDist) Less(i, j int) bool { return b.dists[i] < b.dists[j] }
+func (b byDist) Swap(i, j int) {
+	b.navaids[i], b.navaids[j] = b.navaids[j], b.navaids[i]
+	b.dists[i], b.dists[j] = b.dists[j], b.dists[i]
+}
+
+// VOR, VOR/DME, VORTAC or VOT
+func (nav Navaid) IsVor() bool {
+	return strings.HasPrefix(nav.Type, "VOR")
+}
+
+func (nav Navaid) IsOperational() bool {
+	return strings.HasPrefix(nav.Status, "OPERATIONAL")
+}
+
+// e.g., 115.40 MHz or 329 kHz
+func (nav Navaid) FrequencyString() string {
+	if nav.Type == "NDB" || nav.Type == "NDB/DME" || nav.Type == "MARINE NDB" {
+		return fmt.Sprintf("%.0f kHz", nav.Frequency)
+	}
+	return fmt.Sprintf("%.2f MHz", nav.Frequency)
+}
+
+func (v navEntry) parse() (Navaid, error) {
+	lat, err := parseAptLatOrLon(v.lat)
+	if err != nil {
+		return Navaid{}, err
+	}
+	lon, err := parseAptLatOrLon(v.lon)
+	if err != nil {
+		return Navaid{}, err
+	}
+	nav := Navaid{
+		Id:            v.id,
+		Type:          v.kind,
+		Name:          v.name,
+		City:          v.city,
+		State:         v.state,
+		Class:         v.class,
+		Coord:         geo.NewCoord(lat, lon),
+		Channel:       v.channel,
+		ServiceVolume: parseServiceVolume(v.vorVolume, v.dmeVolume, v.class),
+		Status:        v.status,
+	}
+	if len(v.elevation) != 0 {
+		if nav.Elevation, err = parseAptAlt(v.elevation); err != nil {
+			return Navaid{}, fmt.Errorf("Error parsing navaid %s: %s", v.id, err)
+		}
+	}
+	if len(v.variation) != 0 {
+		if nav.Variation, err = parseAptVariation(v.variation); err != nil {
+			return Navaid{}, fmt.Errorf("Error parsing navaid %s: %s", v.id, err)
+		}
+	}
+	if len(v.variationYear) != 0 {
+		if nav.VariationYear, err = strconv.Atoi(v.variationYear); err != nil {
+			return Navaid{}, fmt.Errorf("Error parsing navaid %s: Invalid variation epoch: %s", v.id, v.variationYear)
+		}
+	}
+	if len(v.frequency) != 0 {
+		if nav.Frequency, err = strconv.ParseFloat(v.frequency, 64); err != nil {
+			return Navaid{}, fmt.Errorf("Error parsing navaid %s: Invalid frequency: %s", v.id, v.frequency)
+		}
+	}
+	return nav, nil
+}
+
+func parseNav(r io.Reader) (Navaids, error) {
+	navaids := Navaids{
+		data: make(map[string][]navEntry),
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		l := s.Text()
+		if getField(l, 1, 4) != "NAV1" {
+			// Remarks, fan markers and holding pattern records
+			continue
+		}
+		e := parseNavEntry(l)
+		navaids.data[e.id] = append(navaids.data[e.id], e)
+	}
+	if err := s.Err(); err != nil {
+		return Navaids{}, errors.New("Error parsing NAV: " + err.Error())
+	}
+	return navaids, nil
+}
+
+func parseNavEntry(l string) navEntry {
+	return navEntry{
+		id:            getField(l, 5, 4),
+		kind:          getField(l, 9, 20),
+		name:          getField(l, 43, 30),
+		city:          getField(l, 73, 40),
+		state:         getField(l, 143, 2),
+		class:         getField(l, 282, 11),
+		lat:           getField(l, 386, 11),
+		lon:           getField(l, 411, 11),
+		elevation:     getField(l, 473, 7),
+		variation:     getField(l, 480, 5),
+		variationYear: getField(l, 485, 4),
+		channel:       getField(l, 526, 4),
+		frequency:     getField(l, 530, 6),
+		vorVolume:     getField(l, 573, 2),
+		dmeVolume:     getField(l, 575, 2),
+		status:        getField(l, 767, 30),
+	}
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/geo"
+	"strconv"
+)
+
+type Runway struct {
+	Id       string // e.g., 08/26
+	LengthFt int
+	WidthFt  int
+	Surface  string // Type and condition, e.g., ASPH-G
+	Ends     []RunwayEnd
+}
+
+type RunwayEnd struct {
+	Id           string // e.g., 08
+	TrueHeading  int    // Zero if not published
+	Coord        *geo.Coord
+	Elevation    *float64 // Feet
+	DisplacedFt  int      // Displaced threshold distance from the runway end
+	RightTraffic bool
+}
+
+type rwyEntry struct {
+	id, length, width, surface string
+	ends                       []rwyEndEntry
+}
+
+type rwyEndEntry struct {
+	id, heading, lat, lon, elevation, displaced, rightTraffic string
+}
+
+// Base and reciprocal end fields are at the same positions, 222 apart
+const rwy_reciprocal_offset = 222
+
+func parseRwyEntry(l string) rwyEntry {
+	r := rwyEntry{
+		id:      getField(l, 17, 7),
+		length:  getField(l, 24, 5),
+		width:   getField(l, 29, 4),
+		surface: getField(l, 33, 12),
+	}
+	for _, o := range []uint{0, rwy_reciprocal_offset} {
+		end := rwyEndEntry{
+			id:           getField(l, 66+o, 3),
+			heading:      getField(l, 69+o, 3),
+			rightTraffic: getField(l, 82+o, 1),
+			lat:          getField(l, 104+o, 12),
+			lon:          getField(l, 131+o, 12),
+			elevation:    getField(l, 143+o, 7),
+			displaced:    getField(l, 218+o, 4),
+		}
+		if len(end.id) != 0 {
+			r.ends = append(r.ends, end)
+		}
+	}
+	return r
+}
+
+// Unpublished values are left as zero or nil
+func (r rwyEntry) parse() (Runway, error) {
+	rwy := Runway{Id: r.id, Surface: r.surface}
+	var err error
+	if rwy.LengthFt, err = optionalInt(r.length); err != nil {
+		return Runway{}, errors.New("Invalid runway " + r.id + " length: " + r.length)
+	} else if rwy.WidthFt, err = optionalInt(r.width); err != nil {
+		return Runway{}, errors.New("Invalid runway " + r.id + " width: " + r.width)
+	}
+	for _, e := range r.ends {
+		end := RunwayEnd{Id: e.id, RightTraffic: e.rightTraffic == "Y"}
+		if end.TrueHeading, err = optionalInt(e.heading); err != nil {
+			return Runway{}, errors.New("Invalid runway " + e.id + " true heading: " + e.heading)
+		} else if end.DisplacedFt, err = optionalInt(e.displaced); err != nil {
+			return Runway{}, errors.New("Invalid runway " + e.id + " displaced threshold: " + e.displaced)
+		}
+		if len(e.lat) != 0 && len(e.lon) != 0 {
+			lat, errLat := parseAptLatOrLon(e.lat)
+			lon, errLon := parseAptLatOrLon(e.lon)
+			if errLat != nil || errLon != nil {
+				return Runway{}, errors.New("Invalid runway " + e.id + " position: " + e.lat + " " + e.lon)
+			}
+			c := geo.NewCoord(lat, lon)
+			end.Coord = &c
+		}
+		if len(e.elevation) != 0 {
+			elev, err := strconv.ParseFloat(e.elevation, 64)
+			if err != nil {
+				return Runway{}, errors.New("Invalid runway " + e.id + " elevation: " + e.elevation)
+			}
+			end.Elevation = &elev
+		}
+		rwy.Ends = append(rwy.Ends, end)
+	}
+	return rwy, nil
+}
+
+func optionalInt(s string) (int, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
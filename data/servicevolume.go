@@ -0,0 +1,89 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VOR standard service volume class, see AIM 1-1-8
+type ServiceVolume string
+
+const (
+	UnknownVolume  ServiceVolume = ""
+	TerminalVolume ServiceVolume = "T"
+	LowVolume      ServiceVolume = "L"
+	HighVolume     ServiceVolume = "H"
+	// Expanded volumes of the VOR minimum operational network
+	VorLowVolume  ServiceVolume = "VL"
+	VorHighVolume ServiceVolume = "VH"
+)
+
+// Height above the facility where each service volume begins
+const service_volume_floor_ft = 1000
+
+type volumeTier struct {
+	ceilingFt float64 // Above the facility
+	rangeNM   float64
+}
+
+var serviceVolumeTiers = map[ServiceVolume][]volumeTier{
+	TerminalVolume: {{12000, 25}},
+	LowVolume:      {{18000, 40}},
+	HighVolume:     {{14500, 40}, {18000, 100}, {45000, 130}, {60000, 100}},
+	VorLowVolume:   {{5000, 40}, {18000, 70}},
+	VorHighVolume:  {{5000, 40}, {14500, 70}, {18000, 100}, {45000, 130}, {60000, 100}},
+}
+
+// Range at a height above the facility, zero outside of the volume
+func (s ServiceVolume) RangeNM(aboveFt float64) float64 {
+	if aboveFt < service_volume_floor_ft {
+		return 0
+	}
+	for _, t := range serviceVolumeTiers[s] {
+		if aboveFt <= t.ceilingFt {
+			return t.rangeNM
+		}
+	}
+	return 0
+}
+
+// e.g., high altitude
+func (s ServiceVolume) String() string {
+	switch s {
+	case TerminalVolume:
+		return "terminal"
+	case LowVolume:
+		return "low altitude"
+	case HighVolume:
+		return "high altitude"
+	case VorLowVolume:
+		return "VOR low altitude"
+	case VorHighVolume:
+		return "VOR high altitude"
+	}
+	return "unknown"
+}
+
+// The VOR volume, otherwise the DME volume or the class prefix, e.g., the H
+// of H-VORTAC
+func parseServiceVolume(vor, dme, class string) ServiceVolume {
+	candidates := []string{vor, strings.TrimPrefix(dme, "D")}
+	if i := strings.Index(class, "-"); i > 0 {
+		candidates = append(candidates, class[:i])
+	}
+	for _, c := range candidates {
+		if _, exists := serviceVolumeTiers[ServiceVolume(c)]; exists {
+			return ServiceVolume(c)
+		}
+	}
+	return UnknownVolume
+}
+
+// e.g., 40 NM to 5000 ft, 70 NM to 18000 ft
+func (s ServiceVolume) Limits() string {
+	limits := []string{}
+	for _, t := range serviceVolumeTiers[s] {
+		limits = append(limits, fmt.Sprintf("%.0f NM to %.0f ft", t.rangeNM, t.ceilingFt))
+	}
+	return strings.Join(limits, ", ")
+}
@@ -0,0 +1,16 @@
+package geo
+
+import "math"
+
+// Corners of a lat/lon box enclosing all points within nm of c.
+// Flat-earth approximation, the box is only intended as a coarse pre-filter.
+func BoundingBox(c Coord, nm float64) (sw, ne Coord) {
+	dLat := nm / 60
+	dLon := 180.0
+	if cosLat := math.Cos(Deg2Rad(c.lat)); cosLat > 0 {
+		dLon = math.Min(180, nm/(60*cosLat))
+	}
+	sw = NewCoord(math.Max(-90, c.lat-dLat), math.Max(-180, c.lon-dLon))
+	ne = NewCoord(math.Min(90, c.lat+dLat), math.Min(180, c.lon+dLon))
+	return sw, ne
+}
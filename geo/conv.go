@@ -29,3 +29,13 @@ func Rad2Compass(rad float64) float64 {
 func Compass2Rad(compass float64) float64 {
 	return math.Pi/2 - Deg2Rad(compass)
 }
+
+const nm_per_statute_mile = 0.868976
+
+func StatuteMiles2NM(sm float64) float64 {
+	return sm * nm_per_statute_mile
+}
+
+func NM2StatuteMiles(nm float64) float64 {
+	return nm / nm_per_statute_mile
+}
@@ -18,12 +18,13 @@ func (c Coord) Lon() float64 {
 	return c.lon
 }
 
-func (c Coord) AddToLat(nm float64) Coord {
+// Flat-earth offsets, see Destination for moves over larger distances
+func (c Coord) AddToLat(nm float64) (Coord, error) {
 	lat := c.lat + nm/60
 	if lat > 90 || lat < -90 {
-		panic(fmt.Sprintf("Impossible latitude: %.4f", lat))
+		return ErrCoord(), fmt.Errorf("Impossible latitude: %.4f", lat)
 	}
-	return NewCoord(lat, c.lon)
+	return NewCoord(lat, c.lon), nil
 }
 
 func (c Coord) AddToLon(nm float64) Coord {
@@ -53,13 +54,18 @@ func ErrCoord() Coord {
 	return Coord{math.NaN(), math.NaN()}
 }
 
-// LAT,LON in decimal notation
-// TODO: Support HH MM SS and HHMMSS formats
+// LAT,LON in decimal notation, degrees and minutes with hemisphere letters,
+// e.g., 40°02'21"N 105°13'34"W or N40 02.35 W105 13.57, or NASR style,
+// e.g., 400221N1051334W
 func ParseLatLon(coord string) (Coord, error) {
 	var lat, lon float64
-	if _, err := fmt.Sscanf(coord, "%f,%f", &lat, &lon); err != nil {
-		return ErrCoord(), errors.New("invalid lon,lat coordinate: " + coord)
-	} else {
+	if _, err := fmt.Sscanf(coord, "%f,%f", &lat, &lon); err == nil {
 		return NewCoord(lat, lon), nil
+	} else if c, err := parseCompact(coord); err == nil {
+		return c, nil
+	} else if c, err := parseHemispheres(coord); err == nil {
+		return c, nil
+	} else {
+		return ErrCoord(), errors.New("invalid lat,lon coordinate: " + coord)
 	}
 }
@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"errors"
+	"strings"
+)
+
+// Notation for printing coordinates
+type CoordFormat int
+
+const (
+	FormatDecimal CoordFormat = iota
+	FormatDMS
+	FormatDDM
+	FormatNASR
+	FormatUTM
+	FormatMGRS
+)
+
+var coordFormatNames = map[string]CoordFormat{
+	"decimal": FormatDecimal,
+	"dms":     FormatDMS,
+	"ddm":     FormatDDM,
+	"nasr":    FormatNASR,
+	"utm":     FormatUTM,
+	"mgrs":    FormatMGRS,
+}
+
+func ParseCoordFormat(s string) (CoordFormat, error) {
+	if f, exists := coordFormatNames[strings.ToLower(s)]; exists {
+		return f, nil
+	}
+	return FormatDecimal, errors.New("Unknown coordinate format, expected decimal, dms, ddm, nasr, utm or mgrs: " + s)
+}
+
+func (c Coord) Format(f CoordFormat) (string, error) {
+	switch f {
+	case FormatDMS:
+		return c.DMS(), nil
+	case FormatDDM:
+		return c.DDM(), nil
+	case FormatNASR:
+		return c.NASR(), nil
+	case FormatUTM:
+		u, err := c.UTM()
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	case FormatMGRS:
+		return c.MGRS(mgrs_max_digits)
+	}
+	return c.String(), nil
+}
+
+// A coordinate in any of the formats accepted by ParseLatLon, ParseUTM or
+// ParseMGRS
+func ParseCoord(s string) (Coord, error) {
+	if c, err := ParseLatLon(s); err == nil {
+		return c, nil
+	} else if c, err := ParseUTM(s); err == nil {
+		return c, nil
+	} else if c, err := ParseMGRS(s); err == nil {
+		return c, nil
+	}
+	return ErrCoord(), errors.New("Invalid coordinate: " + s)
+}
@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NASR style degrees, minutes and seconds, e.g., 400221N1051334W
+var compactRegexp = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2}(?:\.\d+)?)([NS])(\d{3})(\d{2})(\d{2}(?:\.\d+)?)([EW])$`)
+
+// Symbols separating degrees, minutes and seconds
+var dmsSymbols = strings.NewReplacer("°", " ", "º", " ", "'", " ", "′", " ", "\"", " ", "″", " ", ",", " ")
+
+// Hemisphere letters, either before or after each of latitude and longitude
+var hemispheres = strings.NewReplacer("N", " N ", "S", " S ", "E", " E ", "W", " W ")
+
+// Degrees, minutes and seconds, or degrees and decimal minutes, with
+// hemisphere letters, e.g., 40°02'21"N 105°13'34"W or N40 02.35 W105 13.57
+func parseHemispheres(s string) (Coord, error) {
+	e := errors.New("invalid degrees and minutes coordinate: " + s)
+	fields := strings.Fields(hemispheres.Replace(dmsSymbols.Replace(strings.ToUpper(s))))
+	if len(fields) == 0 {
+		return ErrCoord(), e
+	}
+	isLetter := func(f string) bool {
+		return f == "N" || f == "S" || f == "E" || f == "W"
+	}
+	// Each group is a hemisphere letter and its numbers
+	type group struct {
+		hemisphere string
+		values     []string
+	}
+	groups := []group{}
+	prefix := isLetter(fields[0])
+	current := group{}
+	for _, f := range fields {
+		if !isLetter(f) {
+			current.values = append(current.values, f)
+		} else if prefix {
+			if current.hemisphere != "" {
+				groups = append(groups, current)
+			}
+			current = group{hemisphere: f}
+		} else {
+			current.hemisphere = f
+			groups = append(groups, current)
+			current = group{}
+		}
+	}
+	if prefix {
+		groups = append(groups, current)
+	} else if len(current.values) != 0 {
+		return ErrCoord(), e
+	}
+	if len(groups) != 2 {
+		return ErrCoord(), e
+	}
+
+	lat, lon := math.NaN(), math.NaN()
+	for _, g := range groups {
+		v, err := parseDegrees(g.values)
+		if err != nil {
+			return ErrCoord(), e
+		}
+		if g.hemisphere == "S" || g.hemisphere == "W" {
+			v = -v
+		}
+		if g.hemisphere == "N" || g.hemisphere == "S" {
+			lat = v
+		} else {
+			lon = v
+		}
+	}
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return ErrCoord(), e
+	}
+	return NewCoord(lat, lon), nil
+}
+
+// Degrees, then optionally minutes and seconds, only the last fractional
+func parseDegrees(values []string) (float64, error) {
+	if len(values) == 0 || len(values) > 3 {
+		return math.NaN(), errors.New("expected degrees, minutes and seconds")
+	}
+	v, scale := 0.0, 1.0
+	for i, s := range values {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || (i > 0 && f >= 60) || (i < len(values)-1 && strings.Contains(s, ".")) {
+			return math.NaN(), errors.New("invalid degrees, minutes or seconds: " + s)
+		}
+		v += f / scale
+		scale *= 60
+	}
+	return v, nil
+}
+
+func parseCompact(s string) (Coord, error) {
+	m := compactRegexp.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil {
+		return ErrCoord(), errors.New("invalid NASR coordinate: " + s)
+	}
+	lat, errLat := parseDegrees(m[1:4])
+	lon, errLon := parseDegrees(m[5:8])
+	if errLat != nil || errLon != nil || lat > 90 || lon > 180 {
+		return ErrCoord(), errors.New("invalid NASR coordinate: " + s)
+	}
+	if m[4] == "S" {
+		lat = -lat
+	}
+	if m[8] == "W" {
+		lon = -lon
+	}
+	return NewCoord(lat, lon), nil
+}
+
+// Whole degrees and minutes, and seconds rounded to the given decimals
+func splitDms(v float64, decimals int) (int, int, float64) {
+	scale := math.Pow(10, float64(decimals))
+	total := math.Round(math.Abs(v)*3600*scale) / scale
+	d := int(total / 3600)
+	m := int((total - float64(d)*3600) / 60)
+	return d, m, total - float64(d)*3600 - float64(m)*60
+}
+
+// Whole degrees and minutes rounded to the given decimals
+func splitDdm(v float64, decimals int) (int, float64) {
+	scale := math.Pow(10, float64(decimals))
+	total := math.Round(math.Abs(v)*60*scale) / scale
+	d := int(total / 60)
+	return d, total - float64(d)*60
+}
+
+func hemisphere(v float64, positive, negative string) string {
+	if v < 0 {
+		return negative
+	}
+	return positive
+}
+
+// e.g., 40°02'21.2"N 105°13'34.0"W
+func (c Coord) DMS() string {
+	latD, latM, latS := splitDms(c.lat, 1)
+	lonD, lonM, lonS := splitDms(c.lon, 1)
+	return fmt.Sprintf("%02d°%02d'%04.1f\"%s %03d°%02d'%04.1f\"%s",
+		latD, latM, latS, hemisphere(c.lat, "N", "S"),
+		lonD, lonM, lonS, hemisphere(c.lon, "E", "W"))
+}
+
+// e.g., N40 02.35 W105 13.57
+func (c Coord) DDM() string {
+	latD, latM := splitDdm(c.lat, 2)
+	lonD, lonM := splitDdm(c.lon, 2)
+	return fmt.Sprintf("%s%02d %05.2f %s%03d %05.2f",
+		hemisphere(c.lat, "N", "S"), latD, latM,
+		hemisphere(c.lon, "E", "W"), lonD, lonM)
+}
+
+// e.g., 400221N1051334W
+func (c Coord) NASR() string {
+	latD, latM, latS := splitDms(c.lat, 0)
+	lonD, lonM, lonS := splitDms(c.lon, 0)
+	return fmt.Sprintf("%02d%02d%02.0f%s%03d%02d%02.0f%s",
+		latD, latM, latS, hemisphere(c.lat, "N", "S"),
+		lonD, lonM, lonS, hemisphere(c.lon, "E", "W"))
+}
@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"testing"
+)
+
+func checkCoord(t *testing.T, name string, got, want Coord, tolerance float64) {
+	checkNear(t, name+" lat", got.Lat(), want.Lat(), tolerance)
+	checkNear(t, name+" lon", got.Lon(), want.Lon(), tolerance)
+}
+
+// Hemispheres in each quadrant
+var (
+	boulder = NewCoord(dms(40, 2, 21.1), dms(-105, 13, 34.0))
+	sydney  = NewCoord(dms(-33, 51, 24.5), dms(151, 12, 55.1))
+	bergen  = NewCoord(dms(60, 23, 24.0), dms(5, 19, 12.0))
+	lima    = NewCoord(dms(-12, 2, 36.0), dms(-77, 2, 24.0))
+)
+
+func TestParseHemispheres(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want Coord
+	}{
+		{`40°02'21.1"N 105°13'34.0"W`, boulder},
+		{`40°02′21.1″N, 105°13′34″W`, boulder},
+		{"N40 02 21.1 W105 13 34", boulder},
+		{"40 02 21.1 N 105 13 34 W", boulder},
+		{"n40 02.3517 w105 13.5667", boulder},
+		// Longitude first
+		{"W105 13 34 N40 02 21.1", boulder},
+		{`33°51'24.5"S 151°12'55.1"E`, sydney},
+		{"S33 51.4083 E151 12.9183", sydney},
+		{"N60 23.40 E005 19.20", bergen},
+		{"S12 02.6 W077 02.4", lima},
+		{"40N 105W", NewCoord(40, -105)},
+		{"N40.5 W105.25", NewCoord(40.5, -105.25)},
+	} {
+		got, err := parseHemispheres(c.s)
+		if err != nil {
+			t.Errorf("%s: %s", c.s, err)
+			continue
+		}
+		checkCoord(t, c.s, got, c.want, 1e-4/60)
+	}
+}
+
+func TestParseHemispheresInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"40 02 21 105 13 34",
+		"N40 02 21",
+		"N40 N105",
+		"E40 W105",
+		"N40 02 21 W105 13 34 E",
+		"N40 60 W105",
+		"N40 02.5 30 W105",
+		"N40 02 21 05 W105",
+		"N91 W105",
+		"N40 W181",
+	} {
+		if c, err := parseHemispheres(s); err == nil {
+			t.Errorf("%q: got %s, want an error", s, c)
+		}
+	}
+}
+
+func TestParseCompact(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want Coord
+	}{
+		{"400221N1051334W", NewCoord(dms(40, 2, 21), dms(-105, 13, 34))},
+		{"400221.10N1051334.00W", boulder},
+		{"335124S1511255E", NewCoord(dms(-33, 51, 24), dms(151, 12, 55))},
+		{"602324n0051912e", bergen},
+	} {
+		got, err := parseCompact(c.s)
+		if err != nil {
+			t.Errorf("%s: %s", c.s, err)
+			continue
+		}
+		checkCoord(t, c.s, got, c.want, 1e-9)
+	}
+	for _, s := range []string{"400221N105134W", "400221X1051334W", "406021N1051334W", "910000N1051334W", "400221N1810000W"} {
+		if c, err := parseCompact(s); err == nil {
+			t.Errorf("%s: got %s, want an error", s, c)
+		}
+	}
+}
+
+func TestFormatDegrees(t *testing.T) {
+	for _, c := range []struct {
+		c              Coord
+		dms, ddm, nasr string
+	}{
+		{boulder, `40°02'21.1"N 105°13'34.0"W`, "N40 02.35 W105 13.57", "400221N1051334W"},
+		{sydney, `33°51'24.5"S 151°12'55.1"E`, "S33 51.41 E151 12.92", "335125S1511255E"},
+		{bergen, `60°23'24.0"N 005°19'12.0"E`, "N60 23.40 E005 19.20", "602324N0051912E"},
+		{lima, `12°02'36.0"S 077°02'24.0"W`, "S12 02.60 W077 02.40", "120236S0770224W"},
+		// Seconds rounding up carries into the minutes and degrees
+		{NewCoord(dms(39, 59, 59.99), dms(-104, 59, 59.99)), `40°00'00.0"N 105°00'00.0"W`, "N40 00.00 W105 00.00", "400000N1050000W"},
+	} {
+		if got := c.c.DMS(); got != c.dms {
+			t.Errorf("%s DMS: got %s, want %s", c.c, got, c.dms)
+		}
+		if got := c.c.DDM(); got != c.ddm {
+			t.Errorf("%s DDM: got %s, want %s", c.c, got, c.ddm)
+		}
+		if got := c.c.NASR(); got != c.nasr {
+			t.Errorf("%s NASR: got %s, want %s", c.c, got, c.nasr)
+		}
+	}
+}
+
+func TestDegreesRoundTrip(t *testing.T) {
+	for _, c := range []Coord{boulder, sydney, bergen, lima, NewCoord(0, 0), NewCoord(-89.99, 179.99)} {
+		if got, err := ParseLatLon(c.DMS()); err != nil {
+			t.Errorf("%s DMS: %s", c.DMS(), err)
+		} else {
+			checkCoord(t, c.DMS(), got, c, 0.05/3600)
+		}
+		if got, err := ParseLatLon(c.DDM()); err != nil {
+			t.Errorf("%s DDM: %s", c.DDM(), err)
+		} else {
+			checkCoord(t, c.DDM(), got, c, 0.005/60)
+		}
+		if got, err := ParseLatLon(c.NASR()); err != nil {
+			t.Errorf("%s NASR: %s", c.NASR(), err)
+		} else {
+			checkCoord(t, c.NASR(), got, c, 0.5/3600)
+		}
+	}
+}
@@ -25,35 +25,37 @@ func arcLength(a, b Coord) float64 {
 	return math.Atan2(math.Sqrt(num1*num1+num2*num2), den)
 }
 
-func in1stOr2ndQuadrant(a, b float64) bool {
-	if a*b >= 0 { // both positive or both negative
-		// don't have to wrap +180 to -180, so strictly greater comparison works
-		return b > a
-	} else if a > 0 { // && b < 0
-		return b-a < -180
-	} else { // a < 0 && b > 0
-		return b-a < 180
-	}
-}
-
 func InitialHeadingCompass(orig, dest Coord) (float64, error) {
 	if orig == dest {
 		return math.NaN(), errors.New("Undefined heading between two identical locations")
 	}
-	// See https://math.stackexchange.com/questions/1715008/compute-angle-between-two-points-in-a-sphere
-	north := Coord{90, 0}.AsVect3()
-	b := orig.AsVect3()
-	c := dest.AsVect3()
-	n1 := b.Cross(c)
-	n2 := north.Cross(b)
-	r := Rad2Deg(n1.AngleBetween(n2))
-	// 1st & 2nd differ from 3rd & 4th quadrants due to arccos giving the acute
-	// angle from north, rather than the angle in a fixed direction of rotation
-	if in1stOr2ndQuadrant(orig.lon, dest.lon) {
-		// 1st or 2nd quadrant
-		return 180 - r, nil
-	} else {
-		// 3rd or 4th quadrant
-		return 180 + r, nil
+	return Wrap360(Rad2Deg(initialBearing(orig, dest))), nil
+}
+
+// Initial great circle bearing, radians clockwise from true north, defined
+// even when both points share a meridian
+// See https://www.movable-type.co.uk/scripts/latlong.html
+func initialBearing(a, b Coord) float64 {
+	lat1, lat2 := Deg2Rad(a.lat), Deg2Rad(b.lat)
+	deltaLon := Deg2Rad(b.lon - a.lon)
+	y := math.Sin(deltaLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(deltaLon)
+	return math.Atan2(y, x)
+}
+
+// Point a fraction of the way along the great circle from a to b
+// See https://www.movable-type.co.uk/scripts/latlong.html
+func IntermediatePoint(a, b Coord, fraction float64) Coord {
+	d := math.Abs(arcLength(a, b))
+	if d == 0 {
+		return a
 	}
+	lon1, lat1 := Deg2Rad(a.lon), Deg2Rad(a.lat)
+	lon2, lat2 := Deg2Rad(b.lon), Deg2Rad(b.lat)
+	wa := math.Sin((1-fraction)*d) / math.Sin(d)
+	wb := math.Sin(fraction*d) / math.Sin(d)
+	x := wa*math.Cos(lat1)*math.Cos(lon1) + wb*math.Cos(lat2)*math.Cos(lon2)
+	y := wa*math.Cos(lat1)*math.Sin(lon1) + wb*math.Cos(lat2)*math.Sin(lon2)
+	z := wa*math.Sin(lat1) + wb*math.Sin(lat2)
+	return NewCoord(Rad2Deg(math.Atan2(z, math.Sqrt(x*x+y*y))), Rad2Deg(math.Atan2(y, x)))
 }
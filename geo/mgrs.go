@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Military Grid Reference System, UTM with 100 km squares identified by
+// letters in the standard (AA) lettering scheme
+// See https://en.wikipedia.org/wiki/Military_Grid_Reference_System
+
+// Column letters repeat every three zones, row letters every 2000 km
+const mgrs_column_letters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+const mgrs_row_letters = "ABCDEFGHJKLMNPQRSTUV"
+const mgrs_square_m = 100000
+const mgrs_row_cycle_m = 2000000
+
+// Digits in each of the easting and northing, 5 is a 1 meter square
+const mgrs_max_digits = 5
+
+// Allows for the round trip error of the projection, well under a centimeter
+const mgrs_epsilon_m = 0.01
+
+// e.g., 13TDE7654331234 or 13T DE 76543 31234
+var mgrsRegexp = regexp.MustCompile(`^(\d{1,2})\s*([C-HJ-NP-X])\s*([A-HJ-NP-Z])([A-HJ-NP-V])\s*(\d+)\s*(\d*)$`)
+
+// e.g., 13T DE 76543 31234 with 5 digits
+func (c Coord) MGRS(digits int) (string, error) {
+	if digits < 1 || digits > mgrs_max_digits {
+		return "", fmt.Errorf("MGRS precision must be from 1 to %d digits", mgrs_max_digits)
+	}
+	u, err := c.UTM()
+	if err != nil {
+		return "", err
+	}
+	// Truncated to the south west corner of the square, allowing for rounding
+	easting, northing := u.Easting+mgrs_epsilon_m, u.Northing+mgrs_epsilon_m
+	e100k := int(math.Floor(easting / mgrs_square_m))
+	n100k := int(math.Floor(northing/mgrs_square_m)) % (mgrs_row_cycle_m / mgrs_square_m)
+	col := mgrs_column_letters[((u.Zone-1)%3)*8+e100k-1]
+	row := mgrs_row_letters[(n100k+mgrsRowOffset(u.Zone))%len(mgrs_row_letters)]
+	scale := math.Pow(10, float64(mgrs_max_digits-digits))
+	e := int(math.Mod(easting, mgrs_square_m) / scale)
+	n := int(math.Mod(northing, mgrs_square_m) / scale)
+	return fmt.Sprintf("%d%c %c%c %0*d %0*d", u.Zone, u.Band, col, row, digits, e, digits, n), nil
+}
+
+// Even zones start their row letters at F
+func mgrsRowOffset(zone int) int {
+	if zone%2 == 0 {
+		return 5
+	}
+	return 0
+}
+
+// The south west corner of the grid square, e.g., 13TDE7654331234
+func ParseMGRS(s string) (Coord, error) {
+	e := errors.New("invalid MGRS coordinate: " + s)
+	m := mgrsRegexp.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return ErrCoord(), e
+	}
+	zone, _ := strconv.Atoi(m[1])
+	band := m[2][0]
+	digits := m[5] + m[6]
+	if zone < 1 || zone > 60 || len(digits)%2 != 0 || len(digits)/2 > mgrs_max_digits {
+		return ErrCoord(), e
+	}
+	if m[6] != "" && len(m[5]) != len(m[6]) {
+		return ErrCoord(), e
+	}
+
+	// Grid square
+	set := (zone - 1) % 3
+	col := strings.IndexByte(mgrs_column_letters, m[3][0]) - set*8
+	if col < 0 || col >= 8 {
+		return ErrCoord(), e
+	}
+	rows := len(mgrs_row_letters)
+	row := (strings.IndexByte(mgrs_row_letters, m[4][0]) - mgrsRowOffset(zone) + rows) % rows
+
+	// Position within the square
+	half := len(digits) / 2
+	scale := math.Pow(10, float64(mgrs_max_digits-half))
+	east, _ := strconv.Atoi(digits[:half])
+	north, _ := strconv.Atoi(digits[half:])
+	u := UTM{
+		Zone:     zone,
+		Band:     band,
+		Easting:  float64((col+1)*mgrs_square_m) + float64(east)*scale,
+		Northing: float64(row*mgrs_square_m) + float64(north)*scale,
+	}
+
+	// Row letters repeat, so find the cycle within the latitude band. The
+	// band's lower edge has its least northing on the central meridian in
+	// the north, the margin covers its curvature in the south.
+	bandLat := float64(utm_min_lat + 8*strings.IndexByte(utm_bands, band))
+	_, minNorthing := utmProject(NewCoord(bandLat, utmCentralMeridian(zone)), zone)
+	for u.Northing < minNorthing-mgrs_square_m {
+		u.Northing += mgrs_row_cycle_m
+	}
+	return u.Coord()
+}
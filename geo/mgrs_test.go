@@ -0,0 +1,115 @@
+package geo
+
+import (
+	"testing"
+)
+
+func TestMGRS(t *testing.T) {
+	for _, c := range []struct {
+		c      Coord
+		digits int
+		want   string
+	}{
+		{NewCoord(43.642567, -79.387139), 5, "17T PJ 30084 33438"},
+		{NewCoord(38.8895, -77.0353), 5, "18S UJ 23478 06483"},
+		{boulder, 5, "13T DE 80710 32131"},
+		{boulder, 3, "13T DE 807 321"},
+		{boulder, 1, "13T DE 8 3"},
+		// Even zones start their rows at F
+		{sydney, 5, "56H LH 34901 52288"},
+		{bergen, 5, "32V KN 97230 00510"},
+		{NewCoord(56.5, 2.9), 4, "31V DC 9384 6173"},
+		{NewCoord(78.22, 15.65), 5, "33X WG 14813 83004"},
+		{NewCoord(72, 9), 2, "33X TV 93 99"},
+		{NewCoord(83.9, 21), 2, "35X MP 28 20"},
+		{NewCoord(80, 41.9), 2, "37X EJ 56 82"},
+		// Southern hemisphere, just below the equator
+		{NewCoord(-0.0001, -0.0001), 5, "30M ZE 33967 99988"},
+		{NewCoord(0.0001, 0.0001), 5, "31N AA 66032 00011"},
+	} {
+		got, err := c.c.MGRS(c.digits)
+		if err != nil {
+			t.Errorf("%s: %s", c.c, err)
+		} else if got != c.want {
+			t.Errorf("%s: got %s, want %s", c.c, got, c.want)
+		}
+	}
+	if s, err := boulder.MGRS(0); err == nil {
+		t.Errorf("Got %s, want an error with no digits", s)
+	}
+	if s, err := boulder.MGRS(6); err == nil {
+		t.Errorf("Got %s, want an error with 6 digits", s)
+	}
+	if s, err := NewCoord(85, 0).MGRS(5); err == nil {
+		t.Errorf("Got %s, want an error north of 84N", s)
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	for _, c := range []struct {
+		s         string
+		want      Coord
+		tolerance float64
+	}{
+		{"18SUJ2348306479", NewCoord(38.8895, -77.0353), 1e-4},
+		{"18S UJ 23483 06479", NewCoord(38.8895, -77.0353), 1e-4},
+		{"17tpj3008433439", NewCoord(43.642567, -79.387139), 1e-4},
+		{"56HLH3490152288", sydney, 1e-4},
+		{"33XWG1481383004", NewCoord(78.22, 15.65), 1e-4},
+	} {
+		got, err := ParseMGRS(c.s)
+		if err != nil {
+			t.Errorf("%s: %s", c.s, err)
+			continue
+		}
+		checkCoord(t, c.s, got, c.want, c.tolerance)
+	}
+
+	// The south west corner of a 10 km square
+	got, err := ParseMGRS("13T DE 8 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := UTM{Zone: 13, Band: 'T', Easting: 480000, Northing: 4430000}.Coord()
+	checkCoord(t, "10 km square", got, want, 1e-9)
+
+	for _, s := range []string{
+		"13TDE807113213",
+		"13TDE 807 3213",
+		"13TDE807113213456789",
+		"61TDE8071132132",
+		"13TIE8071132132",
+		"13TDW8071132132",
+		// Column letters are from another zone's set
+		"13TJE8071132132",
+		"13DE8071132132",
+	} {
+		if c, err := ParseMGRS(s); err == nil {
+			t.Errorf("%s: got %s, want an error", s, c)
+		}
+	}
+}
+
+func TestMGRSRoundTrip(t *testing.T) {
+	for _, c := range []Coord{boulder, sydney, bergen, lima,
+		NewCoord(56.5, 2.9), NewCoord(63.9, 11.9), NewCoord(72.5, 8.9), NewCoord(83.9, 21.5),
+		NewCoord(-79.9, -179.9), NewCoord(-0.0001, -0.0001), NewCoord(0.0001, 0.0001), NewCoord(-45, 91)} {
+		s, err := c.MGRS(mgrs_max_digits)
+		if err != nil {
+			t.Errorf("%s: %s", c, err)
+			continue
+		}
+		got, err := ParseMGRS(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		// Truncated to the meter
+		if d := DistanceNM(got, c) * 1852; d > 1.5 {
+			t.Errorf("%s: got %s, %.2f m from %s", s, got, d, c)
+		}
+		if again, err := got.MGRS(mgrs_max_digits); err != nil || again != s {
+			t.Errorf("%s: formatted again as %s", s, again)
+		}
+	}
+}
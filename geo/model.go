@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Earth model used for distances and courses
+type Model int
+
+const (
+	Spherical Model = iota
+	WGS84
+)
+
+// Model used by DistanceNM and CourseCompass
+var DefaultModel = WGS84
+
+func (m Model) String() string {
+	if m == Spherical {
+		return "sphere"
+	}
+	return "wgs84"
+}
+
+func ParseModel(s string) (Model, error) {
+	switch strings.ToLower(s) {
+	case "sphere", "spherical":
+		return Spherical, nil
+	case "wgs84", "wgs-84", "ellipsoid":
+		return WGS84, nil
+	}
+	return WGS84, errors.New("Unknown earth model, expected sphere or wgs84: " + s)
+}
+
+// Distance with initial and final courses using the default model. The
+// ellipsoidal solution falls back to the sphere for nearly antipodal points.
+func Inverse(a, b Coord) (Geodesic, error) {
+	if a == b {
+		return Geodesic{}, errors.New("Undefined heading between two identical locations")
+	}
+	if DefaultModel == WGS84 {
+		if g, err := VincentyInverse(a, b); err == nil {
+			return g, nil
+		}
+	}
+	return Geodesic{
+		DistNM:         GlobeDistNM(a, b),
+		InitialAzimuth: Wrap360(Rad2Deg(initialBearing(a, b))),
+		FinalAzimuth:   Wrap360(Rad2Deg(initialBearing(b, a)) - 180),
+	}, nil
+}
+
+// Distance using the default model, zero for identical points
+func DistanceNM(a, b Coord) float64 {
+	if g, err := Inverse(a, b); err == nil {
+		return g.DistNM
+	}
+	return 0
+}
+
+// Initial true course using the default model
+func CourseCompass(orig, dest Coord) (float64, error) {
+	g, err := Inverse(orig, dest)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return g.InitialAzimuth, nil
+}
+
+// Point reached by moving distNM from start along the initial true bearing
+// (compass degrees), using the default model
+func Destination(start Coord, bearing, distNM float64) (Coord, error) {
+	if math.IsNaN(start.lat) || math.IsNaN(start.lon) || start.lat > 90 || start.lat < -90 {
+		return ErrCoord(), fmt.Errorf("Invalid starting position: %s", start)
+	} else if math.IsNaN(bearing) || math.IsInf(bearing, 0) || math.IsNaN(distNM) || math.IsInf(distNM, 0) {
+		return ErrCoord(), fmt.Errorf("Invalid move of %f NM on bearing %f", distNM, bearing)
+	}
+	if distNM < 0 {
+		bearing, distNM = bearing+180, -distNM
+	}
+	var c Coord
+	if DefaultModel == WGS84 {
+		var err error
+		if c, _, err = VincentyDirect(start, Wrap360(bearing), distNM); err != nil {
+			return ErrCoord(), err
+		}
+	} else {
+		c = sphericalDestination(start, Wrap360(bearing), distNM)
+	}
+	if math.IsNaN(c.lat) || math.IsNaN(c.lon) {
+		return ErrCoord(), fmt.Errorf("Unable to move %.1f NM on bearing %.0f from %s", distNM, bearing, start)
+	}
+	return c, nil
+}
+
+// See https://www.movable-type.co.uk/scripts/latlong.html
+func sphericalDestination(start Coord, bearing, distNM float64) Coord {
+	d := distNM / avg_earth_radius_nm
+	theta := Deg2Rad(bearing)
+	lat1, lon1 := Deg2Rad(start.lat), Deg2Rad(start.lon)
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return NewCoord(Rad2Deg(lat2), Wrap360(Rad2Deg(lon2)+180)-180)
+}
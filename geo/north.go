@@ -0,0 +1,26 @@
+package geo
+
+// Reference for directions in compass degrees
+type North int
+
+const (
+	TrueNorth North = iota
+	MagneticNorth
+)
+
+// Suffix used for directions, e.g., 310M
+func (n North) String() string {
+	if n == MagneticNorth {
+		return "M"
+	}
+	return "T"
+}
+
+// Magnetic variation is west positive, as in data.Apt
+func TrueToMagnetic(deg, variation float64) float64 {
+	return Wrap360(deg + variation)
+}
+
+func MagneticToTrue(deg, variation float64) float64 {
+	return Wrap360(deg - variation)
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Rhumb lines (loxodromes) cross every meridian at the same angle, so are
+// flown on a constant true heading. They're longer than the great circle
+// between the same points, negligibly so for short legs.
+// See https://en.wikipedia.org/wiki/Rhumb_line
+
+// Changes in latitude below this, in radians, are treated as due east or west
+const rhumb_epsilon = 1e-12
+
+// Ellipsoid with semi-major axis in NM, or a sphere if e2 is zero
+type ellipsoid struct {
+	a  float64
+	e2 float64 // First eccentricity squared
+}
+
+func modelEllipsoid() ellipsoid {
+	if DefaultModel == WGS84 {
+		return ellipsoid{a: wgs84_a / meters_per_nm, e2: wgs84_f * (2 - wgs84_f)}
+	}
+	return ellipsoid{a: avg_earth_radius_nm, e2: 0}
+}
+
+// Distance along the meridian from the equator to latitude phi
+func (el ellipsoid) meridianArc(phi float64) float64 {
+	e2 := el.e2
+	e4, e6 := e2*e2, e2*e2*e2
+	return el.a * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// Latitude at a distance along the meridian, by Newton's method
+func (el ellipsoid) meridianLat(m float64) float64 {
+	phi := m / el.a
+	for i := 0; i < 10; i++ {
+		s := math.Sin(phi)
+		w := 1 - el.e2*s*s
+		dm := el.a * (1 - el.e2) / (w * math.Sqrt(w))
+		phi -= (el.meridianArc(phi) - m) / dm
+	}
+	return phi
+}
+
+func (el ellipsoid) isometricLat(phi float64) float64 {
+	e := math.Sqrt(el.e2)
+	return math.Atanh(math.Sin(phi)) - e*math.Atanh(e*math.Sin(phi))
+}
+
+// Radius of the parallel at latitude phi
+func (el ellipsoid) parallelRadius(phi float64) float64 {
+	s := math.Sin(phi)
+	return el.a * math.Cos(phi) / math.Sqrt(1-el.e2*s*s)
+}
+
+// Rhumb line distance and constant true bearing (compass degrees) from a to
+// b, using the default model
+func RhumbInverse(a, b Coord) (float64, float64, error) {
+	if a == b {
+		return 0, math.NaN(), errors.New("Undefined heading between two identical locations")
+	}
+	el := modelEllipsoid()
+	phi1, phi2 := Deg2Rad(a.lat), Deg2Rad(b.lat)
+	dLon := Deg2Rad(Wrap360(b.lon-a.lon+180) - 180)
+	if math.Abs(phi2-phi1) < rhumb_epsilon {
+		return math.Abs(dLon) * el.parallelRadius(phi1), Wrap360(Rad2Deg(math.Atan2(dLon, 0))), nil
+	}
+	dPsi := el.isometricLat(phi2) - el.isometricLat(phi1)
+	theta := math.Atan2(dLon, dPsi)
+	dist := (el.meridianArc(phi2) - el.meridianArc(phi1)) / math.Cos(theta)
+	return dist, Wrap360(Rad2Deg(theta)), nil
+}
+
+func RhumbDistNM(a, b Coord) float64 {
+	d, _, _ := RhumbInverse(a, b)
+	return d
+}
+
+func RhumbBearing(a, b Coord) (float64, error) {
+	_, bearing, err := RhumbInverse(a, b)
+	return bearing, err
+}
+
+// Point reached by holding a constant true bearing for distNM, using the
+// default model. Fails for lines that would reach a pole.
+func RhumbDestination(start Coord, bearing, distNM float64) (Coord, error) {
+	if math.IsNaN(start.lat) || math.IsNaN(start.lon) || math.IsNaN(bearing) || math.IsNaN(distNM) {
+		return ErrCoord(), errors.New("Invalid rhumb line")
+	}
+	el := modelEllipsoid()
+	theta := Deg2Rad(bearing)
+	phi1 := Deg2Rad(start.lat)
+	m1 := el.meridianArc(phi1)
+	m2 := m1 + distNM*math.Cos(theta)
+	if math.Abs(m2) >= el.meridianArc(math.Pi/2) {
+		return ErrCoord(), fmt.Errorf("Rhumb line of %.1f NM on bearing %.0f from %s passes a pole", distNM, bearing, start)
+	}
+	phi2 := el.meridianLat(m2)
+	var dLon float64
+	if math.Abs(phi2-phi1) < rhumb_epsilon {
+		dLon = distNM * math.Sin(theta) / el.parallelRadius(phi1)
+	} else {
+		dLon = math.Tan(theta) * (el.isometricLat(phi2) - el.isometricLat(phi1))
+	}
+	return NewCoord(Rad2Deg(phi2), Wrap360(start.lon+Rad2Deg(dLon)+180)-180), nil
+}
@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// Position relative to the great circle leg from start to end, using the
+// spherical model. Cross track distances are positive right of course.
+// See https://www.movable-type.co.uk/scripts/latlong.html
+func CrossTrackNM(start, end, p Coord) float64 {
+	d13, dTheta := trackAngles(start, end, p)
+	return avg_earth_radius_nm * math.Asin(math.Sin(d13)*math.Sin(dTheta))
+}
+
+// Distance from start to the point on the leg's great circle closest to p,
+// negative if p is behind start
+func AlongTrackNM(start, end, p Coord) float64 {
+	d13, dTheta := trackAngles(start, end, p)
+	dxt := math.Asin(math.Sin(d13) * math.Sin(dTheta))
+	cos := math.Max(-1, math.Min(1, math.Cos(d13)/math.Cos(dxt)))
+	along := math.Acos(cos) * avg_earth_radius_nm
+	if math.Cos(dTheta) < 0 {
+		return -along
+	}
+	return along
+}
+
+// Point on the leg, between start and end inclusive, closest to p
+func ClosestPoint(start, end, p Coord) Coord {
+	leg := GlobeDistNM(start, end)
+	if leg == 0 {
+		return start
+	}
+	along := math.Max(0, math.Min(leg, AlongTrackNM(start, end, p)))
+	return IntermediatePoint(start, end, along/leg)
+}
+
+// Angular distance from start to p, and the angle between the leg and p
+func trackAngles(start, end, p Coord) (float64, float64) {
+	d13 := math.Abs(arcLength(start, p))
+	if d13 == 0 || start == end {
+		return 0, 0
+	}
+	return d13, initialBearing(start, p) - initialBearing(start, end)
+}
+
+// Point along a route with the course and distance to the next point
+type Checkpoint struct {
+	Pos    Coord
+	Course float64 // Initial true course to the next point, NaN for the last
+	DistNM float64 // To the next point
+}
+
+// Evenly spaced points along the great circle from a to b, including both
+// ends, at most everyNM apart
+func GreatCirclePoints(a, b Coord, everyNM float64) ([]Coord, error) {
+	if math.IsNaN(everyNM) || everyNM <= 0 {
+		return nil, errors.New("Invalid spacing, must be a positive distance")
+	}
+	n := int(math.Ceil(GlobeDistNM(a, b) / everyNM))
+	if n < 1 {
+		n = 1
+	}
+	points := make([]Coord, n+1)
+	for i := range points {
+		points[i] = IntermediatePoint(a, b, float64(i)/float64(n))
+	}
+	return points, nil
+}
+
+// Courses and distances between successive points, using the default model
+func Checkpoints(points []Coord) ([]Checkpoint, error) {
+	c := make([]Checkpoint, len(points))
+	for i, p := range points {
+		c[i] = Checkpoint{Pos: p, Course: math.NaN()}
+		if i+1 == len(points) {
+			break
+		}
+		g, err := Inverse(p, points[i+1])
+		if err != nil {
+			return nil, err
+		}
+		c[i].Course, c[i].DistNM = g.InitialAzimuth, g.DistNM
+	}
+	return c, nil
+}
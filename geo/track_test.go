@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Legs along a meridian, where the start, end and position can share a
+// longitude
+var (
+	northbound = [2]Coord{NewCoord(40, -105), NewCoord(42, -105)}
+	eastbound  = [2]Coord{NewCoord(40, -105), NewCoord(40, -100)}
+)
+
+func checkNear(t *testing.T, name string, got, want, tolerance float64) {
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s: got %.4f, want %.4f", name, got, want)
+	}
+}
+
+func TestTrackNorthSouthLeg(t *testing.T) {
+	start, end := northbound[0], northbound[1]
+	// A degree east at 41N is about 45 NM, right of a northbound course
+	p := NewCoord(41, -104)
+	checkNear(t, "Cross track", CrossTrackNM(start, end, p), 45.3, 0.1)
+	checkNear(t, "Along track", AlongTrackNM(start, end, p), 60.3, 0.1)
+	c := ClosestPoint(start, end, p)
+	checkNear(t, "Closest point longitude", c.Lon(), -105, 1e-9)
+	checkNear(t, "Closest point latitude", c.Lat(), 41.004, 0.001)
+
+	// And left of the reciprocal course
+	checkNear(t, "Southbound cross track", CrossTrackNM(end, start, p), -45.3, 0.1)
+}
+
+func TestTrackDueSouthOfStart(t *testing.T) {
+	start, end := northbound[0], northbound[1]
+	p := NewCoord(39, -105)
+	checkNear(t, "Cross track", CrossTrackNM(start, end, p), 0, 1e-9)
+	checkNear(t, "Along track", AlongTrackNM(start, end, p), -GlobeDistNM(start, p), 1e-6)
+	if c := ClosestPoint(start, end, p); GlobeDistNM(c, start) > 1e-6 {
+		t.Errorf("Closest point: got %s, want the start %s", c, start)
+	}
+}
+
+func TestTrackDueNorthOfEnd(t *testing.T) {
+	start, end := northbound[0], northbound[1]
+	p := NewCoord(43, -105)
+	checkNear(t, "Cross track", CrossTrackNM(start, end, p), 0, 1e-9)
+	checkNear(t, "Along track", AlongTrackNM(start, end, p), GlobeDistNM(start, p), 1e-6)
+	if c := ClosestPoint(start, end, p); GlobeDistNM(c, end) > 1e-6 {
+		t.Errorf("Closest point: got %s, want the end %s", c, end)
+	}
+}
+
+func TestTrackDueNorthOfStart(t *testing.T) {
+	start, end := eastbound[0], eastbound[1]
+	// Half a degree north is 30 NM, left of an eastbound course. The great
+	// circle bends away from the parallel, so p is slightly ahead of start.
+	p := NewCoord(40.5, -105)
+	checkNear(t, "Cross track", CrossTrackNM(start, end, p), -30.0, 0.05)
+	checkNear(t, "Along track", AlongTrackNM(start, end, p), 0.84, 0.05)
+	c := ClosestPoint(start, end, p)
+	if math.IsNaN(c.Lat()) || math.IsNaN(c.Lon()) || GlobeDistNM(c, start) > 1 {
+		t.Errorf("Closest point: got %s, want near the start %s", c, start)
+	}
+}
+
+func TestInitialHeadingMeridian(t *testing.T) {
+	start, end := northbound[0], northbound[1]
+	for _, c := range []struct {
+		a, b Coord
+		want float64
+	}{
+		{start, end, 0},
+		{end, start, 180},
+		{NewCoord(-10, 30), NewCoord(10, 30), 0},
+	} {
+		h, err := InitialHeadingCompass(c.a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkNear(t, "Heading from "+c.a.String(), h, c.want, 1e-9)
+	}
+	if _, err := InitialHeadingCompass(start, start); err == nil {
+		t.Error("Expected an error for identical locations")
+	}
+}
@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Universal Transverse Mercator on the WGS-84 ellipsoid, using Krüger's
+// series which are accurate to well under a meter within a zone
+// See https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system
+const utm_k0 = 0.9996
+const utm_false_easting = 500000.0
+const utm_false_northing_south = 10000000.0
+
+// UTM is only defined between these latitudes, the polar regions use UPS
+const utm_min_lat = -80
+const utm_max_lat = 84
+
+// Latitude bands of 8 degrees from 80S, X is extended to 84N
+const utm_bands = "CDEFGHJKLMNPQRSTUVWX"
+
+// e.g., 13T 476543 4431234
+var utmRegexp = regexp.MustCompile(`^(\d{1,2})\s*([C-HJ-NP-X])\s+(\d+(?:\.\d+)?)\s+(\d+(?:\.\d+)?)$`)
+
+type UTM struct {
+	Zone     int
+	Band     byte // Latitude band letter, N and above are north of the equator
+	Easting  float64
+	Northing float64
+}
+
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %.0f %.0f", u.Zone, u.Band, u.Easting, u.Northing)
+}
+
+func (u UTM) north() bool {
+	return u.Band >= 'N'
+}
+
+// Krüger series coefficients, in terms of the third flattening
+type kruger struct {
+	a                  float64 // Rectifying radius, meters
+	alpha, beta, delta [3]float64
+}
+
+func utmSeries() kruger {
+	n := wgs84_f / (2 - wgs84_f)
+	n2, n3 := n*n, n*n*n
+	return kruger{
+		a:     wgs84_a / (1 + n) * (1 + n2/4 + n2*n2/64),
+		alpha: [3]float64{n/2 - 2*n2/3 + 5*n3/16, 13*n2/48 - 3*n3/5, 61 * n3 / 240},
+		beta:  [3]float64{n/2 - 2*n2/3 + 37*n3/96, n2/48 + n3/15, 17 * n3 / 480},
+		delta: [3]float64{2*n - 2*n2/3 - 2*n3, 7*n2/3 - 8*n3/5, 56 * n3 / 15},
+	}
+}
+
+func utmCentralMeridian(zone int) float64 {
+	return float64(zone*6 - 183)
+}
+
+// Zone including the exceptions around Norway and Svalbard
+func utmZone(lat, lon float64) int {
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 1
+	}
+	if lat >= 56 && lat < 64 && lon >= 3 && lon < 12 {
+		return 32
+	}
+	if lat >= 72 {
+		if lon >= 0 && lon < 9 {
+			return 31
+		} else if lon >= 9 && lon < 21 {
+			return 33
+		} else if lon >= 21 && lon < 33 {
+			return 35
+		} else if lon >= 33 && lon < 42 {
+			return 37
+		}
+	}
+	return zone
+}
+
+func utmBand(lat float64) byte {
+	i := int(math.Floor((lat - utm_min_lat) / 8))
+	if i >= len(utm_bands) {
+		i = len(utm_bands) - 1
+	}
+	return utm_bands[i]
+}
+
+func (c Coord) UTM() (UTM, error) {
+	if math.IsNaN(c.lat) || c.lat < utm_min_lat || c.lat > utm_max_lat {
+		return UTM{}, fmt.Errorf("UTM is only defined from %dS to %dN, not %s", -utm_min_lat, utm_max_lat, c)
+	}
+	zone := utmZone(c.lat, c.lon)
+	u := UTM{Zone: zone, Band: utmBand(c.lat)}
+	u.Easting, u.Northing = utmProject(c, zone)
+	return u, nil
+}
+
+// Easting and northing in a zone, which may be an adjacent zone's
+func utmProject(c Coord, zone int) (float64, float64) {
+	k := utmSeries()
+	phi := Deg2Rad(c.lat)
+	dLon := Deg2Rad(Wrap360(c.lon-utmCentralMeridian(zone)+180) - 180)
+	e := math.Sqrt(wgs84_f * (2 - wgs84_f))
+	t := math.Sinh(math.Atanh(math.Sin(phi)) - e*math.Atanh(e*math.Sin(phi)))
+	xi := math.Atan2(t, math.Cos(dLon))
+	eta := math.Atanh(math.Sin(dLon) / math.Sqrt(1+t*t))
+	easting, northing := eta, xi
+	for j := 1; j <= 3; j++ {
+		a := k.alpha[j-1]
+		easting += a * math.Cos(2*float64(j)*xi) * math.Sinh(2*float64(j)*eta)
+		northing += a * math.Sin(2*float64(j)*xi) * math.Cosh(2*float64(j)*eta)
+	}
+	easting = utm_false_easting + utm_k0*k.a*easting
+	northing = utm_k0 * k.a * northing
+	if c.lat < 0 {
+		northing += utm_false_northing_south
+	}
+	return easting, northing
+}
+
+func (u UTM) Coord() (Coord, error) {
+	if u.Zone < 1 || u.Zone > 60 || !strings.ContainsRune(utm_bands, rune(u.Band)) {
+		return ErrCoord(), fmt.Errorf("Invalid UTM zone %d%c", u.Zone, u.Band)
+	}
+	k := utmSeries()
+	northing := u.Northing
+	if !u.north() {
+		northing -= utm_false_northing_south
+	}
+	xi := northing / (utm_k0 * k.a)
+	eta := (u.Easting - utm_false_easting) / (utm_k0 * k.a)
+	xi2, eta2 := xi, eta
+	for j := 1; j <= 3; j++ {
+		b := k.beta[j-1]
+		xi2 -= b * math.Sin(2*float64(j)*xi) * math.Cosh(2*float64(j)*eta)
+		eta2 -= b * math.Cos(2*float64(j)*xi) * math.Sinh(2*float64(j)*eta)
+	}
+	chi := math.Asin(math.Sin(xi2) / math.Cosh(eta2))
+	phi := chi
+	for j := 1; j <= 3; j++ {
+		phi += k.delta[j-1] * math.Sin(2*float64(j)*chi)
+	}
+	lon := utmCentralMeridian(u.Zone) + Rad2Deg(math.Atan2(math.Sinh(eta2), math.Cos(xi2)))
+	c := NewCoord(Rad2Deg(phi), Wrap360(lon+180)-180)
+	if math.IsNaN(c.lat) || math.IsNaN(c.lon) {
+		return ErrCoord(), errors.New("Invalid UTM coordinate: " + u.String())
+	}
+	return c, nil
+}
+
+// e.g., 13T 476543 4431234
+func ParseUTM(s string) (Coord, error) {
+	m := utmRegexp.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return ErrCoord(), errors.New("invalid UTM coordinate: " + s)
+	}
+	zone, _ := strconv.Atoi(m[1])
+	easting, _ := strconv.ParseFloat(m[3], 64)
+	northing, _ := strconv.ParseFloat(m[4], 64)
+	return UTM{Zone: zone, Band: m[2][0], Easting: easting, Northing: northing}.Coord()
+}
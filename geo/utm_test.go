@@ -0,0 +1,124 @@
+package geo
+
+import (
+	"testing"
+)
+
+func TestUTMZone(t *testing.T) {
+	for _, c := range []struct {
+		lat, lon float64
+		zone     int
+	}{
+		{0, -180, 1},
+		{0, 179.99, 60},
+		{0, 180, 1},
+		{40, -105, 13},
+		{-34, 151, 56},
+		// 32V is widened west over Norway, leaving 31V narrow
+		{56, 2.99, 31},
+		{56, 3, 32},
+		{63.99, 11.99, 32},
+		{64, 3, 31},
+		{55.99, 3, 31},
+		{60, 12, 33},
+		// Svalbard has the odd zones only
+		{72, 8.99, 31},
+		{72, 9, 33},
+		{78, 20.99, 33},
+		{78, 21, 35},
+		{84, 32.99, 35},
+		{78, 33, 37},
+		{78, 41.99, 37},
+		{78, 42, 38},
+		{71.99, 9, 32},
+	} {
+		if got := utmZone(c.lat, c.lon); got != c.zone {
+			t.Errorf("%.2f,%.2f: got zone %d, want %d", c.lat, c.lon, got, c.zone)
+		}
+	}
+}
+
+func TestUTMBand(t *testing.T) {
+	for _, c := range []struct {
+		lat  float64
+		band byte
+	}{
+		{-80, 'C'},
+		{-0.01, 'M'},
+		{0, 'N'},
+		{40, 'T'},
+		{56, 'V'},
+		{72, 'X'},
+		{84, 'X'},
+	} {
+		if got := utmBand(c.lat); got != c.band {
+			t.Errorf("%.2f: got band %c, want %c", c.lat, got, c.band)
+		}
+	}
+}
+
+func TestUTM(t *testing.T) {
+	for _, c := range []struct {
+		c    Coord
+		want string
+	}{
+		// CN Tower
+		{NewCoord(43.642567, -79.387139), "17T 630084 4833439"},
+		{boulder, "13T 480710 4432132"},
+		{sydney, "56H 334901 6252288"},
+		{bergen, "32V 297230 6700510"},
+		{lima, "18L 277920 8667829"},
+		{NewCoord(78.22, 15.65), "33X 514814 8683004"},
+		{NewCoord(0, -3), "30N 500000 0"},
+	} {
+		u, err := c.c.UTM()
+		if err != nil {
+			t.Errorf("%s: %s", c.c, err)
+		} else if got := u.String(); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.c, got, c.want)
+		}
+	}
+	for _, c := range []Coord{NewCoord(-80.01, 0), NewCoord(84.01, 0)} {
+		if u, err := c.UTM(); err == nil {
+			t.Errorf("%s: got %s, want an error", c, u)
+		}
+	}
+}
+
+func TestUTMRoundTrip(t *testing.T) {
+	for _, c := range []Coord{boulder, sydney, bergen, lima,
+		NewCoord(56.5, 2.9), NewCoord(63.9, 11.9), NewCoord(72.5, 8.9), NewCoord(83.9, 21.5),
+		NewCoord(-79.9, -179.9), NewCoord(0.0001, 0.0001), NewCoord(-0.0001, -0.0001)} {
+		u, err := c.UTM()
+		if err != nil {
+			t.Errorf("%s: %s", c, err)
+			continue
+		}
+		got, err := u.Coord()
+		if err != nil {
+			t.Errorf("%s: %s", u, err)
+			continue
+		}
+		checkCoord(t, u.String(), got, c, 1e-8)
+	}
+}
+
+func TestParseUTM(t *testing.T) {
+	got, err := ParseUTM("17t 630084 4833439")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCoord(t, "CN Tower", got, NewCoord(43.642567, -79.387139), 1e-5)
+	// Southern hemisphere bands use the false northing
+	got, err = ParseUTM("56H 334901.2 6252288.8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCoord(t, "Sydney", got, sydney, 1e-5)
+
+	for _, s := range []string{"13 480711 4432133", "13I 480711 4432133", "61T 480711 4432133", "0T 480711 4432133", "13T 480711", "13T DE 80711 32133"} {
+		if c, err := ParseUTM(s); err == nil {
+			t.Errorf("%s: got %s, want an error", s, c)
+		}
+	}
+}
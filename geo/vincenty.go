@@ -0,0 +1,138 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// WGS-84 ellipsoid
+const wgs84_a = 6378137.0 // Semi-major axis, meters
+const wgs84_f = 1 / 298.257223563
+const wgs84_b = wgs84_a * (1 - wgs84_f)
+
+const meters_per_nm = 1852.0
+
+// Iteration limit and convergence threshold (radians, ~0.06 mm)
+const vincenty_max_iterations = 200
+const vincenty_epsilon = 1e-12
+
+// Shortest path between two points on the ellipsoid
+type Geodesic struct {
+	DistNM         float64
+	InitialAzimuth float64 // Compass degrees, true
+	FinalAzimuth   float64 // Compass degrees, true, at the end of the path
+}
+
+// Vincenty's inverse solution on the WGS-84 ellipsoid, accurate to within a
+// millimeter. Fails to converge for nearly antipodal points.
+// See https://en.wikipedia.org/wiki/Vincenty%27s_formulae
+func VincentyInverse(a, b Coord) (Geodesic, error) {
+	if a == b {
+		return Geodesic{DistNM: 0, InitialAzimuth: math.NaN(), FinalAzimuth: math.NaN()}, nil
+	}
+	f := wgs84_f
+	L := Deg2Rad(b.lon - a.lon)
+	u1 := math.Atan((1 - f) * math.Tan(Deg2Rad(a.lat)))
+	u2 := math.Atan((1 - f) * math.Tan(Deg2Rad(b.lat)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	converged := false
+	for i := 0; i < vincenty_max_iterations; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		t1 := cosU2 * sinLambda
+		t2 := cosU1*sinU2 - sinU1*cosU2*cosLambda
+		sinSigma = math.Sqrt(t1*t1 + t2*t2)
+		if sinSigma == 0 {
+			// Coincident points
+			return Geodesic{DistNM: 0, InitialAzimuth: math.NaN(), FinalAzimuth: math.NaN()}, nil
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		} else {
+			// Equatorial line
+			cos2SigmaM = 0
+		}
+		c := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-c)*f*sinAlpha*
+			(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < vincenty_epsilon {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return Geodesic{}, errors.New("Geodesic failed to converge, points are nearly antipodal")
+	}
+
+	uSq := cosSqAlpha * (wgs84_a*wgs84_a - wgs84_b*wgs84_b) / (wgs84_b * wgs84_b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	s := wgs84_b * A * (sigma - deltaSigma)
+
+	sinLambda, cosLambda := math.Sincos(lambda)
+	alpha1 := math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	alpha2 := math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)
+	return Geodesic{
+		DistNM:         s / meters_per_nm,
+		InitialAzimuth: Wrap360(Rad2Deg(alpha1)),
+		FinalAzimuth:   Wrap360(Rad2Deg(alpha2)),
+	}, nil
+}
+
+// Vincenty's direct solution on the WGS-84 ellipsoid: the point distNM from
+// start along the initial azimuth (compass degrees, true), and the azimuth on
+// arrival at that point
+func VincentyDirect(start Coord, azimuth, distNM float64) (Coord, float64, error) {
+	f := wgs84_f
+	s := distNM * meters_per_nm
+	sinAlpha1, cosAlpha1 := math.Sincos(Deg2Rad(azimuth))
+	tanU1 := (1 - f) * math.Tan(Deg2Rad(start.lat))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	sigma1 := math.Atan2(tanU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	uSq := cosSqAlpha * (wgs84_a*wgs84_a - wgs84_b*wgs84_b) / (wgs84_b * wgs84_b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+
+	sigma := s / (wgs84_b * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	converged := false
+	for i := 0; i < vincenty_max_iterations; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+		prev := sigma
+		sigma = s/(wgs84_b*A) + deltaSigma
+		if math.Abs(sigma-prev) < vincenty_epsilon {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return ErrCoord(), math.NaN(), errors.New("Geodesic failed to converge")
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	x := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	lat := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Sqrt(sinAlpha*sinAlpha+x*x))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	c := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+	L := lambda - (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+	lon := Wrap360(start.lon+Rad2Deg(L)+180) - 180
+	alpha2 := math.Atan2(sinAlpha, -x)
+	return NewCoord(Rad2Deg(lat), lon), Wrap360(Rad2Deg(alpha2)), nil
+}
@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func dms(d, m, s float64) float64 {
+	return math.Copysign(math.Abs(d)+m/60+s/3600, d)
+}
+
+// Vincenty's worked example, Flinders Peak to Buninyong
+var (
+	flindersPeak = NewCoord(dms(-37, 57, 3.72030), dms(144, 25, 29.52440))
+	buninyong    = NewCoord(dms(-37, 39, 10.15610), dms(143, 55, 35.38390))
+)
+
+const (
+	flinders_dist_m      = 54972.271
+	flinders_forward_az  = 306 + 52.0/60 + 5.37/3600
+	flinders_reverse_az  = 127 + 10.0/60 + 25.07/3600
+	azimuth_tolerance    = 0.01 / 3600 // Published to hundredths of a second
+	distance_tolerance_m = 0.001
+)
+
+func TestVincentyInverse(t *testing.T) {
+	g, err := VincentyInverse(flindersPeak, buninyong)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := g.DistNM * meters_per_nm; math.Abs(d-flinders_dist_m) > distance_tolerance_m {
+		t.Errorf("Distance: got %.4f m, want %.3f m", d, flinders_dist_m)
+	}
+	if math.Abs(g.InitialAzimuth-flinders_forward_az) > azimuth_tolerance {
+		t.Errorf("Forward azimuth: got %.6f, want %.6f", g.InitialAzimuth, flinders_forward_az)
+	}
+	// The reverse azimuth is from Buninyong back to Flinders Peak
+	if reverse := Wrap360(g.FinalAzimuth + 180); math.Abs(reverse-flinders_reverse_az) > azimuth_tolerance {
+		t.Errorf("Reverse azimuth: got %.6f, want %.6f", reverse, flinders_reverse_az)
+	}
+}
+
+func TestVincentyDirect(t *testing.T) {
+	c, final, err := VincentyDirect(flindersPeak, flinders_forward_az, flinders_dist_m/meters_per_nm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// About 3 cm at this latitude
+	if math.Abs(c.Lat()-buninyong.Lat()) > 3e-7 || math.Abs(c.Lon()-buninyong.Lon()) > 3e-7 {
+		t.Errorf("Destination: got %.8f,%.8f, want %.8f,%.8f", c.Lat(), c.Lon(), buninyong.Lat(), buninyong.Lon())
+	}
+	if reverse := Wrap360(final + 180); math.Abs(reverse-flinders_reverse_az) > azimuth_tolerance {
+		t.Errorf("Reverse azimuth: got %.6f, want %.6f", reverse, flinders_reverse_az)
+	}
+}
+
+func TestInverseNearlyAntipodal(t *testing.T) {
+	defer func(m Model) { DefaultModel = m }(DefaultModel)
+	DefaultModel = WGS84
+	a, b := NewCoord(0, 0), NewCoord(0.5, 179.7)
+	if _, err := VincentyInverse(a, b); err == nil {
+		t.Fatal("Expected Vincenty to fail to converge for nearly antipodal points")
+	}
+	g, err := Inverse(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Falls back to the spherical model
+	if g.DistNM != GlobeDistNM(a, b) {
+		t.Errorf("Distance: got %.3f NM, want the spherical %.3f NM", g.DistNM, GlobeDistNM(a, b))
+	}
+	if math.IsNaN(g.InitialAzimuth) || math.IsNaN(g.FinalAzimuth) {
+		t.Errorf("Azimuths: got %v, %v", g.InitialAzimuth, g.FinalAzimuth)
+	}
+}
+
+func TestInverseSphereNorthSouth(t *testing.T) {
+	defer func(m Model) { DefaultModel = m }(DefaultModel)
+	DefaultModel = Spherical
+	a, b := NewCoord(40, -105), NewCoord(42, -105)
+	for _, c := range []struct {
+		from, to       Coord
+		initial, final float64
+	}{
+		{a, b, 0, 0},
+		{b, a, 180, 180},
+	} {
+		g, err := Inverse(c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(g.DistNM-120) > 0.1 {
+			t.Errorf("Distance: got %.3f NM, want two degrees of latitude", g.DistNM)
+		}
+		if math.Abs(g.InitialAzimuth-c.initial) > 1e-9 || math.Abs(g.FinalAzimuth-c.final) > 1e-9 {
+			t.Errorf("Azimuths from %s: got %v, %v, want %v, %v", c.from, g.InitialAzimuth, g.FinalAzimuth, c.initial, c.final)
+		}
+	}
+}
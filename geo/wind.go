@@ -0,0 +1,26 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// Heading required to hold course against wind.
+// Angles are real angles in radians, see Compass2Rad.
+func WindCorrectedHeading(course, tas float64, wind Vect) float64 {
+	return math.Asin(wind.Magnitude()*math.Sin(wind.AsAngle()-course)/tas) + course
+}
+
+func GroundSpeed(course, heading, tas float64, wind Vect) float64 {
+	return tas*math.Cos(heading-course) - wind.Magnitude()*math.Cos(wind.AsAngle()-course)
+}
+
+// Heading and ground speed to hold course, or an error if the course cannot be held
+func WindTriangle(course, tas float64, wind Vect) (float64, float64, error) {
+	h := WindCorrectedHeading(course, tas, wind)
+	gs := GroundSpeed(course, h, tas, wind)
+	if math.IsNaN(h) || math.IsNaN(gs) || gs <= 0 {
+		return math.NaN(), math.NaN(), errors.New("Course is impossible to achieve under provided parameters")
+	}
+	return h, gs, nil
+}
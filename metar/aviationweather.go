@@ -0,0 +1,95 @@
+package metar
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/aviationweather"
+	"github.com/cragcraig/flight/geo"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const aviationWeatherUrl = "https://aviationweather.gov/api/data/metar"
+
+// Provider backed by the aviationweather.gov data API
+// See https://aviationweather.gov/data/api/
+type AviationWeather struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+func NewAviationWeather() AviationWeather {
+	return AviationWeather{
+		BaseUrl: aviationWeatherUrl,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (a AviationWeather) QueryStations(stations []string, hoursBeforeNow float64) ([]Metar, error) {
+	if metars, err := a.QueryHistory(stations, hoursBeforeNow); err != nil {
+		return []Metar{}, err
+	} else {
+		return mostRecentForEachStation(metars), nil
+	}
+}
+
+func (a AviationWeather) QueryHistory(stations []string, hoursBeforeNow float64) ([]Metar, error) {
+	parameters := url.Values{}
+	parameters.Add("ids", strings.Join(stations, ","))
+	return a.query(parameters, hoursBeforeNow)
+}
+
+func (a AviationWeather) QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Metar, error) {
+	// The API only supports rectangular queries, so filter the corners afterwards
+	nm := geo.StatuteMiles2NM(float64(radius))
+	sw, ne := geo.BoundingBox(coord, nm)
+	parameters := url.Values{}
+	parameters.Add("bbox", fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", sw.Lat(), sw.Lon(), ne.Lat(), ne.Lon()))
+	metars, err := a.query(parameters, hoursBeforeNow)
+	if err != nil {
+		return []Metar{}, err
+	}
+	within := []Metar{}
+	for _, m := range mostRecentForEachStation(metars) {
+		if geo.GlobeDistNM(coord, m.Coord()) <= nm {
+			within = append(within, m)
+		}
+	}
+	return within, nil
+}
+
+func (a AviationWeather) buildQueryUrl(parameters url.Values, hoursBeforeNow float64) string {
+	u, err := url.Parse(a.BaseUrl)
+	if err != nil {
+		panic("bad base url: " + a.BaseUrl)
+	}
+	parameters.Add("format", "xml")
+	parameters.Add("hours", fmt.Sprintf("%.2f", hoursBeforeNow))
+	u.RawQuery = parameters.Encode()
+	return u.String()
+}
+
+func (a AviationWeather) query(parameters url.Values, hoursBeforeNow float64) ([]Metar, error) {
+	queryUrl := a.buildQueryUrl(parameters, hoursBeforeNow)
+	body, err := aviationweather.Fetch(a.Client, queryUrl, "METAR")
+	if err != nil {
+		return []Metar{}, err
+	}
+	if len(body) == 0 {
+		// No matching observations
+		return []Metar{}, nil
+	}
+	return unmarshalXml(body)
+}
+
+// Keeps only the latest observation for each station, preserving response order
+func mostRecentForEachStation(metars []Metar) []Metar {
+	result := []Metar{}
+	for _, i := range aviationweather.MostRecent(len(metars), func(i int) (string, string) {
+		return metars[i].StationId, metars[i].ObservationTime
+	}) {
+		result = append(result, metars[i])
+	}
+	return result
+}
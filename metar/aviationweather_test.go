@@ -0,0 +1,152 @@
+package metar
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Recorded from the data API, trimmed to the fields used
+const metarResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<response>
+  <data num_results="3">
+    <METAR>
+      <raw_text>KBDU 181755Z 27012KT 10SM FEW080 18/M02 A3012</raw_text>
+      <station_id>KBDU</station_id>
+      <observation_time>2026-10-18T17:55:00Z</observation_time>
+      <latitude>40.0394</latitude>
+      <longitude>-105.2258</longitude>
+      <elevation_m>1612</elevation_m>
+    </METAR>
+    <METAR>
+      <raw_text>KDEN 181753Z 24008KT 10SM SCT100 19/M03 A3010</raw_text>
+      <station_id>KDEN</station_id>
+      <observation_time>2026-10-18T17:53:00Z</observation_time>
+      <latitude>39.8466</latitude>
+      <longitude>-104.6562</longitude>
+      <elevation_m>1656</elevation_m>
+    </METAR>
+    <METAR>
+      <raw_text>KBDU 181655Z 26010KT 10SM FEW070 16/M02 A3013</raw_text>
+      <station_id>KBDU</station_id>
+      <observation_time>2026-10-18T16:55:00Z</observation_time>
+      <latitude>40.0394</latitude>
+      <longitude>-105.2258</longitude>
+      <elevation_m>1612</elevation_m>
+    </METAR>
+  </data>
+</response>`
+
+// Stand-in for the data API, recording the last query
+type fakeServer struct {
+	*httptest.Server
+	query url.Values
+	path  string
+}
+
+func newFakeServer(t *testing.T, status int, body string) *fakeServer {
+	f := &fakeServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.path = r.URL.Path
+		f.query = r.URL.Query()
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeServer) provider() AviationWeather {
+	a := NewAviationWeather()
+	a.BaseUrl = f.URL + "/api/data/metar"
+	a.Client = f.Client()
+	return a
+}
+
+func TestQueryUrl(t *testing.T) {
+	f := newFakeServer(t, http.StatusOK, metarResponse)
+	if _, err := f.provider().QueryHistory([]string{"KBDU", "KDEN"}, 2.5); err != nil {
+		t.Fatal(err)
+	}
+	if f.path != "/api/data/metar" {
+		t.Errorf("Path: got %q", f.path)
+	}
+	for k, want := range map[string]string{"ids": "KBDU,KDEN", "format": "xml", "hours": "2.50"} {
+		if got := f.query.Get(k); got != want {
+			t.Errorf("Query %s: got %q, want %q", k, got, want)
+		}
+	}
+}
+
+func TestQueryHistory(t *testing.T) {
+	f := newFakeServer(t, http.StatusOK, metarResponse)
+	metars, err := f.provider().QueryHistory([]string{"KBDU", "KDEN"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metars) != 3 {
+		t.Fatalf("Got %d METARs, want every observation", len(metars))
+	}
+}
+
+func TestQueryStationsMostRecent(t *testing.T) {
+	f := newFakeServer(t, http.StatusOK, metarResponse)
+	metars, err := f.provider().QueryStations([]string{"KBDU", "KDEN"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metars) != 2 {
+		t.Fatalf("Got %d METARs, want one per station", len(metars))
+	}
+	// Response order is preserved
+	if metars[0].StationId != "KBDU" || metars[1].StationId != "KDEN" {
+		t.Errorf("Order: got %s, %s", metars[0].StationId, metars[1].StationId)
+	}
+	if !strings.HasPrefix(metars[0].RawText, "KBDU 181755Z") {
+		t.Errorf("KBDU: got %q, want the latest observation", metars[0].RawText)
+	}
+}
+
+func TestQueryRadius(t *testing.T) {
+	f := newFakeServer(t, http.StatusOK, metarResponse)
+	// KDEN is about 28 statute miles from KBDU
+	metars, err := f.provider().QueryRadius(geo.NewCoord(40.0394, -105.2258), 20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.query.Get("bbox") == "" || f.query.Get("ids") != "" {
+		t.Errorf("Query: got %v, want a bounding box", f.query)
+	}
+	if len(metars) != 1 || metars[0].StationId != "KBDU" {
+		t.Errorf("Got %v, want only KBDU within the radius", metars)
+	}
+	if metars, err = f.provider().QueryRadius(geo.NewCoord(40.0394, -105.2258), 40, 1); err != nil {
+		t.Fatal(err)
+	} else if len(metars) != 2 {
+		t.Errorf("Got %d METARs, want both stations within the radius", len(metars))
+	}
+}
+
+func TestQueryEmpty(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNoContent} {
+		f := newFakeServer(t, status, "")
+		metars, err := f.provider().QueryStations([]string{"KXXX"}, 1)
+		if err != nil {
+			t.Fatalf("Status %d: %s", status, err)
+		}
+		if len(metars) != 0 {
+			t.Errorf("Status %d: got %d METARs, want none", status, len(metars))
+		}
+	}
+}
+
+func TestQueryServerError(t *testing.T) {
+	f := newFakeServer(t, http.StatusInternalServerError, "")
+	if _, err := f.provider().QueryStations([]string{"KBDU"}, 1); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
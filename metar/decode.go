@@ -0,0 +1,400 @@
+package metar
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	in_hg_per_hpa         = 0.0295300
+	statute_mile_meters   = 1609.344
+	knots_per_meter_sec   = 1.943844
+	knots_per_kilometer_h = 0.539957
+)
+
+type Wind struct {
+	Dir      int // True degrees, meaningless if Variable
+	Variable bool
+	Speed    int // Knots
+	Gust     int // Knots, zero if no gusts reported
+	// Variable sector, e.g., 240V300. Both zero if not reported.
+	VarFrom, VarTo int
+}
+
+func (w Wind) IsCalm() bool {
+	return w.Speed == 0 && w.Gust == 0
+}
+
+type Visibility struct {
+	SM          float64
+	LessThan    bool // e.g., M1/4SM
+	GreaterThan bool // e.g., P6SM
+}
+
+type RunwayVisualRange struct {
+	Runway      string
+	Feet        int
+	MaxFeet     int // Upper bound of a variable range, zero if not variable
+	LessThan    bool
+	GreaterThan bool
+	Trend       string // U, D, N or empty
+}
+
+type Weather struct {
+	Intensity  string // "-", "+", "VC" or empty for moderate
+	Descriptor string // e.g., TS, SH, FZ
+	Phenomena  []string
+}
+
+type SkyLayer struct {
+	Cover  string // SKC, CLR, FEW, SCT, BKN, OVC or VV
+	BaseFt int    // AGL, -1 when not applicable or not reported
+	Cloud  string // CB, TCU or empty
+}
+
+func (s SkyLayer) IsCeiling() bool {
+	return (s.Cover == "BKN" || s.Cover == "OVC" || s.Cover == "VV") && s.BaseFt >= 0
+}
+
+type PeakWind struct {
+	Dir, Speed   int
+	Hour, Minute int // Hour is -1 when only minutes were reported
+}
+
+type Remarks struct {
+	StationType      string   // AO1 or AO2
+	SeaLevelPressure *float64 // hPa
+	Temp, Dewpoint   *float64 // Celsius, to the tenth of a degree
+	PeakWind         *PeakWind
+	Raw              string
+}
+
+// Typed representation of a raw METAR or SPECI report
+type Report struct {
+	Type              string // METAR or SPECI
+	Station           string
+	Day, Hour, Minute int
+	Auto, Corrected   bool
+	Wind              *Wind
+	Visibility        *Visibility
+	Rvr               []RunwayVisualRange
+	Weather           []Weather
+	Sky               []SkyLayer
+	Temp, Dewpoint    *float64 // Celsius
+	AltimeterInHg     *float64
+	Remarks           Remarks
+	Unparsed          []string
+}
+
+var (
+	stationRegexp    = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	timeRegexp       = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windRegexp       = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(G(\d{2,3}))?(KT|MPS|KMH)$`)
+	windVarRegexp    = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visSMRegexp      = regexp.MustCompile(`^([MP])?(\d+)?(/(\d+))?SM$`)
+	visMetersRegexp  = regexp.MustCompile(`^(\d{4})(NDV)?$`)
+	rvrRegexp        = regexp.MustCompile(`^R(\d{2}[LRC]?)/([PM])?(\d{4})(V([PM])?(\d{4}))?(FT)?/?([UDN])?$`)
+	weatherRegexp    = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*)$`)
+	skyClearRegexp   = regexp.MustCompile(`^(SKC|CLR|NSC|NCD)$`)
+	skyLayerRegexp   = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	tempRegexp       = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterRegexp  = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	slpRegexp        = regexp.MustCompile(`^SLP(\d{3})$`)
+	tempGroupRegexp  = regexp.MustCompile(`^T([01])(\d{3})(([01])(\d{3}))?$`)
+	peakWindRegexp   = regexp.MustCompile(`^(\d{3})(\d{2,3})/(\d{2})?(\d{2})$`)
+	wholeMilesRegexp = regexp.MustCompile(`^\d$`)
+)
+
+// Decodes a raw METAR or SPECI report, e.g.,
+// METAR KBDU 181753Z AUTO 27012G20KT 240V300 10SM -RA BKN080CB 18/M03 A2984 RMK AO2 SLP102 T01781028
+func Decode(raw string) (Report, error) {
+	tokens := strings.Fields(strings.ToUpper(raw))
+	r := Report{Type: "METAR"}
+	if len(tokens) > 0 && (tokens[0] == "METAR" || tokens[0] == "SPECI") {
+		r.Type = tokens[0]
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 || !stationRegexp.MatchString(tokens[0]) {
+		return Report{}, errors.New("METAR is missing a station identifier: " + raw)
+	}
+	r.Station = tokens[0]
+	tokens = tokens[1:]
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t == "RMK" {
+			r.Remarks = decodeRemarks(tokens[i+1:])
+			break
+		} else if m := timeRegexp.FindStringSubmatch(t); m != nil && r.Wind == nil {
+			r.Day, _ = strconv.Atoi(m[1])
+			r.Hour, _ = strconv.Atoi(m[2])
+			r.Minute, _ = strconv.Atoi(m[3])
+		} else if t == "AUTO" {
+			r.Auto = true
+		} else if t == "COR" {
+			r.Corrected = true
+		} else if w, ok := ParseWind(t); ok {
+			r.Wind = &w
+		} else if m := windVarRegexp.FindStringSubmatch(t); m != nil && r.Wind != nil {
+			r.Wind.VarFrom, _ = strconv.Atoi(m[1])
+			r.Wind.VarTo, _ = strconv.Atoi(m[2])
+		} else if t == "CAVOK" {
+			r.Visibility = &Visibility{SM: 6, GreaterThan: true}
+		} else if v, n, ok := ParseVisibility(tokens[i:]); ok && r.Visibility == nil {
+			r.Visibility = &v
+			i += n - 1
+		} else if rvr, ok := parseRvr(t); ok {
+			r.Rvr = append(r.Rvr, rvr)
+		} else if s, ok := ParseSkyLayer(t); ok {
+			r.Sky = append(r.Sky, s)
+		} else if w, ok := ParseWeather(t); ok {
+			r.Weather = append(r.Weather, w)
+		} else if m := tempRegexp.FindStringSubmatch(t); m != nil {
+			temp := parseSignedTemp(m[1])
+			r.Temp = &temp
+			if m[2] != "" {
+				dew := parseSignedTemp(m[2])
+				r.Dewpoint = &dew
+			}
+		} else if m := altimeterRegexp.FindStringSubmatch(t); m != nil {
+			v, _ := strconv.ParseFloat(m[2], 64)
+			if m[1] == "A" {
+				v = v / 100
+			} else {
+				v = v * in_hg_per_hpa
+			}
+			r.AltimeterInHg = &v
+		} else {
+			r.Unparsed = append(r.Unparsed, t)
+		}
+	}
+	return r, nil
+}
+
+// e.g., 27012KT, VRB03KT, 31015G25KT, 09005MPS
+func ParseWind(t string) (Wind, bool) {
+	m := windRegexp.FindStringSubmatch(t)
+	if m == nil {
+		return Wind{}, false
+	}
+	w := Wind{}
+	if m[1] == "VRB" {
+		w.Variable = true
+	} else {
+		w.Dir, _ = strconv.Atoi(m[1])
+	}
+	w.Speed, _ = strconv.Atoi(m[2])
+	if m[4] != "" {
+		w.Gust, _ = strconv.Atoi(m[4])
+	}
+	if m[5] == "MPS" {
+		w.Speed = int(math.Round(float64(w.Speed) * knots_per_meter_sec))
+		w.Gust = int(math.Round(float64(w.Gust) * knots_per_meter_sec))
+	} else if m[5] == "KMH" {
+		w.Speed = int(math.Round(float64(w.Speed) * knots_per_kilometer_h))
+		w.Gust = int(math.Round(float64(w.Gust) * knots_per_kilometer_h))
+	}
+	return w, true
+}
+
+// Parses a visibility group from the start of tokens, returning the number of
+// tokens consumed. Whole and fractional miles may be split over two tokens.
+// e.g., 10SM, 1/2SM, 1 1/2SM, M1/4SM, P6SM, 9999
+func ParseVisibility(tokens []string) (Visibility, int, bool) {
+	if len(tokens) == 0 {
+		return Visibility{}, 0, false
+	}
+	if len(tokens) > 1 && wholeMilesRegexp.MatchString(tokens[0]) {
+		if v, ok := parseVisibilitySM(tokens[1]); ok && !v.LessThan && !v.GreaterThan && v.SM < 1 {
+			whole, _ := strconv.Atoi(tokens[0])
+			v.SM += float64(whole)
+			return v, 2, true
+		}
+	}
+	if v, ok := parseVisibilitySM(tokens[0]); ok {
+		return v, 1, true
+	}
+	if m := visMetersRegexp.FindStringSubmatch(tokens[0]); m != nil {
+		meters, _ := strconv.Atoi(m[1])
+		if meters == 9999 {
+			return Visibility{SM: 6, GreaterThan: true}, 1, true
+		}
+		return Visibility{SM: float64(meters) / statute_mile_meters}, 1, true
+	}
+	return Visibility{}, 0, false
+}
+
+func parseVisibilitySM(t string) (Visibility, bool) {
+	m := visSMRegexp.FindStringSubmatch(t)
+	if m == nil || m[2] == "" {
+		return Visibility{}, false
+	}
+	v := Visibility{
+		LessThan:    m[1] == "M",
+		GreaterThan: m[1] == "P",
+	}
+	n, _ := strconv.ParseFloat(m[2], 64)
+	if m[4] != "" {
+		d, _ := strconv.ParseFloat(m[4], 64)
+		if d == 0 {
+			return Visibility{}, false
+		}
+		n = n / d
+	}
+	v.SM = n
+	return v, true
+}
+
+// e.g., R26L/2400FT, R08/P6000FT, R17/1200V2000FT/U
+func parseRvr(t string) (RunwayVisualRange, bool) {
+	m := rvrRegexp.FindStringSubmatch(t)
+	if m == nil {
+		return RunwayVisualRange{}, false
+	}
+	rvr := RunwayVisualRange{
+		Runway:      m[1],
+		LessThan:    m[2] == "M",
+		GreaterThan: m[2] == "P" || m[5] == "P",
+		Trend:       m[8],
+	}
+	rvr.Feet, _ = strconv.Atoi(m[3])
+	if m[6] != "" {
+		rvr.MaxFeet, _ = strconv.Atoi(m[6])
+	}
+	return rvr, true
+}
+
+// e.g., -RA, +TSRAGR, VCSH, FZFG, BR
+func ParseWeather(t string) (Weather, bool) {
+	m := weatherRegexp.FindStringSubmatch(t)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return Weather{}, false
+	} else if m[3] == "" && m[2] != "TS" && !(m[1] == "VC" && m[2] == "SH") {
+		// Other descriptors only qualify a phenomenon, e.g., FZ alone isn't weather
+		return Weather{}, false
+	}
+	w := Weather{
+		Intensity:  m[1],
+		Descriptor: m[2],
+	}
+	for i := 0; i+2 <= len(m[3]); i += 2 {
+		w.Phenomena = append(w.Phenomena, m[3][i:i+2])
+	}
+	return w, true
+}
+
+// e.g., CLR, FEW050, BKN080CB, OVC///, VV002
+func ParseSkyLayer(t string) (SkyLayer, bool) {
+	if skyClearRegexp.MatchString(t) {
+		return SkyLayer{Cover: t, BaseFt: -1}, true
+	}
+	m := skyLayerRegexp.FindStringSubmatch(t)
+	if m == nil {
+		return SkyLayer{}, false
+	}
+	s := SkyLayer{
+		Cover:  m[1],
+		BaseFt: -1,
+		Cloud:  m[3],
+	}
+	if m[2] != "///" {
+		h, _ := strconv.Atoi(m[2])
+		s.BaseFt = h * 100
+	}
+	return s, true
+}
+
+// e.g., 18, M03
+func parseSignedTemp(t string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimPrefix(t, "M"), 64)
+	if strings.HasPrefix(t, "M") {
+		return -v
+	}
+	return v
+}
+
+func decodeRemarks(tokens []string) Remarks {
+	r := Remarks{Raw: strings.Join(tokens, " ")}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t == "AO1" || t == "AO2" {
+			r.StationType = t
+		} else if m := slpRegexp.FindStringSubmatch(t); m != nil {
+			v, _ := strconv.ParseFloat(m[1], 64)
+			v = v / 10
+			if v < 50 {
+				v += 1000
+			} else {
+				v += 900
+			}
+			r.SeaLevelPressure = &v
+		} else if m := tempGroupRegexp.FindStringSubmatch(t); m != nil {
+			temp := parseTenths(m[1], m[2])
+			r.Temp = &temp
+			if m[3] != "" {
+				dew := parseTenths(m[4], m[5])
+				r.Dewpoint = &dew
+			}
+		} else if t == "PK" && i+2 < len(tokens) && tokens[i+1] == "WND" {
+			if m := peakWindRegexp.FindStringSubmatch(tokens[i+2]); m != nil {
+				pk := PeakWind{Hour: -1}
+				pk.Dir, _ = strconv.Atoi(m[1])
+				pk.Speed, _ = strconv.Atoi(m[2])
+				if m[3] != "" {
+					pk.Hour, _ = strconv.Atoi(m[3])
+				}
+				pk.Minute, _ = strconv.Atoi(m[4])
+				r.PeakWind = &pk
+				i += 2
+			}
+		}
+	}
+	return r
+}
+
+// T-group temperature, sign digit followed by tenths of a degree
+func parseTenths(sign, tenths string) float64 {
+	v, _ := strconv.ParseFloat(tenths, 64)
+	if sign == "1" {
+		return -v / 10
+	}
+	return v / 10
+}
+
+// Lowest broken, overcast or obscured layer, if any
+func Ceiling(sky []SkyLayer) (int, bool) {
+	for _, s := range sky {
+		if s.IsCeiling() {
+			return s.BaseFt, true
+		}
+	}
+	return 0, false
+}
+
+// FAA flight category for a ceiling and visibility in statute miles
+func FlightCategory(ceilingFt int, hasCeiling bool, visSM float64) string {
+	if (hasCeiling && ceilingFt < 500) || visSM < 1 {
+		return "LIFR"
+	} else if (hasCeiling && ceilingFt < 1000) || visSM < 3 {
+		return "IFR"
+	} else if (hasCeiling && ceilingFt <= 3000) || visSM <= 5 {
+		return "MVFR"
+	}
+	return "VFR"
+}
+
+// Flight category, or empty if visibility was not reported
+func (r Report) FlightCategory() string {
+	if r.Visibility == nil {
+		return ""
+	}
+	c, ok := Ceiling(r.Sky)
+	return FlightCategory(c, ok, r.Visibility.SM)
+}
+
+func (m Metar) Decode() (Report, error) {
+	return Decode(m.RawText)
+}
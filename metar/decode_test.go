@@ -0,0 +1,206 @@
+package metar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Compact forms of the decoded groups, for comparing with the raw text
+func formatWind(w *Wind) string {
+	if w == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%03d@%d", w.Dir, w.Speed)
+	if w.Variable {
+		s = fmt.Sprintf("VRB@%d", w.Speed)
+	}
+	if w.Gust != 0 {
+		s += fmt.Sprintf("G%d", w.Gust)
+	}
+	if w.VarFrom != 0 || w.VarTo != 0 {
+		s += fmt.Sprintf(" %03dV%03d", w.VarFrom, w.VarTo)
+	}
+	return s
+}
+
+func formatWeather(weather []Weather) string {
+	s := []string{}
+	for _, w := range weather {
+		s = append(s, w.Intensity+w.Descriptor+strings.Join(w.Phenomena, ""))
+	}
+	return strings.Join(s, " ")
+}
+
+func formatSky(sky []SkyLayer) string {
+	s := []string{}
+	for _, l := range sky {
+		if l.BaseFt < 0 {
+			s = append(s, l.Cover+l.Cloud)
+		} else {
+			s = append(s, fmt.Sprintf("%s%03d%s", l.Cover, l.BaseFt/100, l.Cloud))
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+func TestDecode(t *testing.T) {
+	for _, c := range []struct {
+		raw                 string
+		kind, station, time string
+		auto, corrected     bool
+		wind                string
+		visSM               float64
+		weather, sky        string
+		temp, dewpoint      float64
+		altimeter           float64
+		category            string
+	}{
+		{
+			raw:  "METAR KDEN 181753Z 24008KT 10SM SCT100 19/M03 A3010 RMK AO2 SLP146 T01941028",
+			kind: "METAR", station: "KDEN", time: "181753",
+			wind: "240@8", visSM: 10, sky: "SCT100",
+			temp: 19, dewpoint: -3, altimeter: 30.10, category: "VFR",
+		},
+		{
+			raw:  "SPECI KORD 181820Z 27015G28KT 1 1/2SM R10L/4500VP6000FT/U +TSRA BR BKN008 OVC020CB 17/16 A2992 RMK AO2 PK WND 28032/1805",
+			kind: "SPECI", station: "KORD", time: "181820",
+			wind: "270@15G28", visSM: 1.5, weather: "+TSRA BR", sky: "BKN008 OVC020CB",
+			temp: 17, dewpoint: 16, altimeter: 29.92, category: "IFR",
+		},
+		{
+			raw:  "KBDU 181755Z AUTO 27005KT 240V300 M1/4SM FZFG VV002 M02/M02 A3012 RMK AO1",
+			kind: "METAR", station: "KBDU", time: "181755", auto: true,
+			wind: "270@5 240V300", visSM: 0.25, weather: "FZFG", sky: "VV002",
+			temp: -2, dewpoint: -2, altimeter: 30.12, category: "LIFR",
+		},
+		{
+			raw:  "KSFO 181756Z COR VRB03KT 4SM HZ VCSH SKC 18/12 A3001",
+			kind: "METAR", station: "KSFO", time: "181756", corrected: true,
+			wind: "VRB@3", visSM: 4, weather: "HZ VCSH", sky: "SKC",
+			temp: 18, dewpoint: 12, altimeter: 30.01, category: "MVFR",
+		},
+		{
+			raw:  "KAPA 181853Z 18010KT 10SM TS SCT050CB 25/20 A2990",
+			kind: "METAR", station: "KAPA", time: "181853",
+			wind: "180@10", visSM: 10, weather: "TS", sky: "SCT050CB",
+			temp: 25, dewpoint: 20, altimeter: 29.90, category: "VFR",
+		},
+		{
+			// 12 m/s, 9999 meters and 1012 hPa
+			raw:  "EGLL 181750Z 22012MPS 9999 -SHRA FEW030CB 14/09 Q1012",
+			kind: "METAR", station: "EGLL", time: "181750",
+			wind: "220@23", visSM: 6, weather: "-SHRA", sky: "FEW030CB",
+			temp: 14, dewpoint: 9, altimeter: 29.88, category: "VFR",
+		},
+	} {
+		r, err := Decode(c.raw)
+		if err != nil {
+			t.Errorf("%s: %s", c.raw, err)
+			continue
+		}
+		check := func(name string, got, want interface{}) {
+			if got != want {
+				t.Errorf("%s %s: got %v, want %v", c.station, name, got, want)
+			}
+		}
+		check("type", r.Type, c.kind)
+		check("station", r.Station, c.station)
+		check("time", fmt.Sprintf("%02d%02d%02d", r.Day, r.Hour, r.Minute), c.time)
+		check("auto", r.Auto, c.auto)
+		check("corrected", r.Corrected, c.corrected)
+		check("wind", formatWind(r.Wind), c.wind)
+		check("weather", formatWeather(r.Weather), c.weather)
+		check("sky", formatSky(r.Sky), c.sky)
+		check("category", r.FlightCategory(), c.category)
+		if r.Visibility == nil || math.Abs(r.Visibility.SM-c.visSM) > 1e-9 {
+			t.Errorf("%s visibility: got %+v, want %.2f SM", c.station, r.Visibility, c.visSM)
+		}
+		if r.Temp == nil || *r.Temp != c.temp || r.Dewpoint == nil || *r.Dewpoint != c.dewpoint {
+			t.Errorf("%s temperature: got %v/%v, want %.0f/%.0f", c.station, r.Temp, r.Dewpoint, c.temp, c.dewpoint)
+		}
+		if r.AltimeterInHg == nil || math.Abs(*r.AltimeterInHg-c.altimeter) > 0.005 {
+			t.Errorf("%s altimeter: got %v, want %.2f", c.station, r.AltimeterInHg, c.altimeter)
+		}
+		if len(r.Unparsed) != 0 {
+			t.Errorf("%s unparsed: got %q", c.station, r.Unparsed)
+		}
+	}
+}
+
+func TestDecodeDetails(t *testing.T) {
+	r, err := Decode("SPECI KORD 181820Z 27015G28KT 1 1/2SM R10L/4500VP6000FT/U +TSRA BR BKN008 OVC020CB 17/16 A2992 RMK AO2 PK WND 28032/1805 SLP132 T01720161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Rvr) != 1 || r.Rvr[0] != (RunwayVisualRange{Runway: "10L", Feet: 4500, MaxFeet: 6000, GreaterThan: true, Trend: "U"}) {
+		t.Errorf("RVR: got %+v", r.Rvr)
+	}
+	rmk := r.Remarks
+	if rmk.StationType != "AO2" || rmk.PeakWind == nil || *rmk.PeakWind != (PeakWind{Dir: 280, Speed: 32, Hour: 18, Minute: 5}) {
+		t.Errorf("Remarks: got %s, peak wind %+v", rmk.StationType, rmk.PeakWind)
+	}
+	if rmk.SeaLevelPressure == nil || math.Abs(*rmk.SeaLevelPressure-1013.2) > 1e-9 {
+		t.Errorf("Sea level pressure: got %v", rmk.SeaLevelPressure)
+	}
+	if rmk.Temp == nil || math.Abs(*rmk.Temp-17.2) > 1e-9 || rmk.Dewpoint == nil || math.Abs(*rmk.Dewpoint-16.1) > 1e-9 {
+		t.Errorf("Remarks temperature: got %v/%v", rmk.Temp, rmk.Dewpoint)
+	}
+	if c, ok := Ceiling(r.Sky); !ok || c != 800 {
+		t.Errorf("Ceiling: got %d, %t", c, ok)
+	}
+}
+
+// Descriptors without a phenomenon are left unparsed
+func TestDecodeBareDescriptor(t *testing.T) {
+	r, err := Decode("KXXX 181756Z 00000KT 10SM FZ CLR 01/M01 A3001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Weather) != 0 || len(r.Unparsed) != 1 || r.Unparsed[0] != "FZ" {
+		t.Errorf("Got weather %q, unparsed %q", formatWeather(r.Weather), r.Unparsed)
+	}
+	if r.Wind == nil || !r.Wind.IsCalm() {
+		t.Errorf("Wind: got %+v, want calm", r.Wind)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "METAR", "METAR 181753Z 24008KT"} {
+		if _, err := Decode(raw); err == nil {
+			t.Errorf("Expected an error for %q", raw)
+		}
+	}
+}
+
+func TestParseWeather(t *testing.T) {
+	for _, c := range []struct {
+		token string
+		want  string // Empty if invalid
+	}{
+		{"-RA", "-RA"},
+		{"+TSRAGR", "+TSRAGR"},
+		{"VCSH", "VCSH"},
+		{"VCTS", "VCTS"},
+		{"TS", "TS"},
+		{"FZFG", "FZFG"},
+		{"BR", "BR"},
+		{"FZ", ""},
+		{"SH", ""},
+		{"-SH", ""},
+		{"BL", ""},
+		{"VC", ""},
+		{"", ""},
+		{"RAX", ""},
+	} {
+		w, ok := ParseWeather(c.token)
+		if got := formatWeather([]Weather{w}); ok != (c.want != "") || (ok && got != c.want) {
+			t.Errorf("%q: got %q, %t, want %q", c.token, got, ok, c.want)
+		}
+	}
+	w, _ := ParseWeather("+TSRAGR")
+	if w.Intensity != "+" || w.Descriptor != "TS" || strings.Join(w.Phenomena, ",") != "RA,GR" {
+		t.Errorf("+TSRAGR: got %+v", w)
+	}
+}
@@ -0,0 +1,229 @@
+package metar
+
+import (
+	"fmt"
+	"strings"
+)
+
+var intensityNames = map[string]string{
+	"-":  "light",
+	"+":  "heavy",
+	"VC": "in the vicinity",
+}
+
+var descriptorNames = map[string]string{
+	"MI": "shallow",
+	"PR": "partial",
+	"BC": "patches of",
+	"DR": "low drifting",
+	"BL": "blowing",
+	"SH": "showers of",
+	"TS": "thunderstorm with",
+	"FZ": "freezing",
+}
+
+var phenomenaNames = map[string]string{
+	"DZ": "drizzle",
+	"RA": "rain",
+	"SN": "snow",
+	"SG": "snow grains",
+	"IC": "ice crystals",
+	"PL": "ice pellets",
+	"GR": "hail",
+	"GS": "small hail",
+	"UP": "unknown precipitation",
+	"BR": "mist",
+	"FG": "fog",
+	"FU": "smoke",
+	"VA": "volcanic ash",
+	"DU": "dust",
+	"SA": "sand",
+	"HZ": "haze",
+	"PY": "spray",
+	"PO": "dust whirls",
+	"SQ": "squalls",
+	"FC": "funnel cloud",
+	"SS": "sandstorm",
+	"DS": "duststorm",
+}
+
+var coverNames = map[string]string{
+	"SKC": "Sky clear",
+	"CLR": "Clear below 12,000 ft",
+	"NSC": "No significant cloud",
+	"NCD": "No cloud detected",
+	"FEW": "Few",
+	"SCT": "Scattered",
+	"BKN": "Broken",
+	"OVC": "Overcast",
+	"VV":  "Vertical visibility",
+}
+
+func (w Wind) String() string {
+	if w.IsCalm() {
+		return "Calm"
+	}
+	var s string
+	if w.Variable {
+		s = fmt.Sprintf("Variable at %d kts", w.Speed)
+	} else {
+		s = fmt.Sprintf("From %03d at %d kts", w.Dir, w.Speed)
+	}
+	if w.Gust != 0 {
+		s += fmt.Sprintf(", gusting %d kts", w.Gust)
+	}
+	if w.VarFrom != 0 || w.VarTo != 0 {
+		s += fmt.Sprintf(", varying between %03d and %03d", w.VarFrom, w.VarTo)
+	}
+	return s
+}
+
+func (v Visibility) String() string {
+	prefix := ""
+	if v.LessThan {
+		prefix = "Less than "
+	} else if v.GreaterThan {
+		prefix = "Greater than "
+	}
+	return fmt.Sprintf("%s%s SM", prefix, strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v.SM), "0"), "."))
+}
+
+func (r RunwayVisualRange) String() string {
+	s := fmt.Sprintf("Runway %s ", r.Runway)
+	if r.LessThan {
+		s += "less than "
+	} else if r.GreaterThan && r.MaxFeet == 0 {
+		s += "greater than "
+	}
+	s += fmt.Sprintf("%d ft", r.Feet)
+	if r.MaxFeet != 0 {
+		s += fmt.Sprintf(" to %d ft", r.MaxFeet)
+	}
+	switch r.Trend {
+	case "U":
+		s += ", increasing"
+	case "D":
+		s += ", decreasing"
+	case "N":
+		s += ", no change"
+	}
+	return s
+}
+
+func (w Weather) String() string {
+	words := []string{}
+	if w.Intensity != "" && w.Intensity != "VC" {
+		words = append(words, intensityNames[w.Intensity])
+	}
+	if w.Descriptor != "" {
+		words = append(words, descriptorNames[w.Descriptor])
+	}
+	phenomena := []string{}
+	for _, p := range w.Phenomena {
+		phenomena = append(phenomena, phenomenaNames[p])
+	}
+	if len(phenomena) != 0 {
+		words = append(words, strings.Join(phenomena, " and "))
+	}
+	s := strings.TrimSuffix(strings.TrimSuffix(strings.Join(words, " "), " with"), " of")
+	if w.Intensity == "VC" {
+		s += " " + intensityNames[w.Intensity]
+	}
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func (s SkyLayer) String() string {
+	str := coverNames[s.Cover]
+	if s.Cloud == "CB" {
+		str += " cumulonimbus"
+	} else if s.Cloud == "TCU" {
+		str += " towering cumulus"
+	}
+	if s.BaseFt >= 0 {
+		str += fmt.Sprintf(" at %d ft", s.BaseFt)
+	} else if s.Cover != "SKC" && s.Cover != "CLR" && s.Cover != "NSC" && s.Cover != "NCD" {
+		str += " at unknown height"
+	}
+	return str
+}
+
+// Plain-English breakdown of the report, one element per line
+func (r Report) Describe() []string {
+	lines := []string{}
+	add := func(label, value string) {
+		lines = append(lines, fmt.Sprintf("%12s:  %s", label, value))
+	}
+
+	kind := "Observation"
+	if r.Type == "SPECI" {
+		kind = "Special observation"
+	}
+	if r.Auto {
+		kind = "Automated " + strings.ToLower(kind)
+	}
+	if r.Corrected {
+		kind += " (corrected)"
+	}
+	add("Report", kind)
+	add("Station", r.Station)
+	add("Time", fmt.Sprintf("Day %d at %02d:%02dZ", r.Day, r.Hour, r.Minute))
+	if r.Wind != nil {
+		add("Wind", r.Wind.String())
+	}
+	if r.Visibility != nil {
+		add("Visibility", r.Visibility.String())
+	}
+	for _, rvr := range r.Rvr {
+		add("RVR", rvr.String())
+	}
+	if len(r.Weather) != 0 {
+		wx := []string{}
+		for _, w := range r.Weather {
+			wx = append(wx, w.String())
+		}
+		add("Weather", strings.Join(wx, ", "))
+	}
+	for _, s := range r.Sky {
+		add("Sky", s.String())
+	}
+	// Prefer the tenth of a degree precision of the remarks T-group
+	temp, dewpoint := r.Temp, r.Dewpoint
+	if r.Remarks.Temp != nil {
+		temp, dewpoint = r.Remarks.Temp, r.Remarks.Dewpoint
+	}
+	if temp != nil {
+		add("Temperature", fmt.Sprintf("%.1f C", *temp))
+	}
+	if dewpoint != nil {
+		add("Dewpoint", fmt.Sprintf("%.1f C", *dewpoint))
+	}
+	if r.AltimeterInHg != nil {
+		add("Altimeter", fmt.Sprintf("%.2f inHg", *r.AltimeterInHg))
+	}
+	if c := r.FlightCategory(); c != "" {
+		add("Category", c)
+	}
+	if r.Remarks.StationType == "AO1" {
+		add("Equipment", "Automated, without precipitation discriminator")
+	} else if r.Remarks.StationType == "AO2" {
+		add("Equipment", "Automated, with precipitation discriminator")
+	}
+	if r.Remarks.SeaLevelPressure != nil {
+		add("SLP", fmt.Sprintf("%.1f hPa", *r.Remarks.SeaLevelPressure))
+	}
+	if r.Remarks.PeakWind != nil {
+		pk := r.Remarks.PeakWind
+		at := fmt.Sprintf(":%02d", pk.Minute)
+		if pk.Hour >= 0 {
+			at = fmt.Sprintf("%02d:%02dZ", pk.Hour, pk.Minute)
+		}
+		add("Peak wind", fmt.Sprintf("From %03d at %d kts at %s", pk.Dir, pk.Speed, at))
+	}
+	if len(r.Remarks.Raw) != 0 {
+		add("Remarks", r.Remarks.Raw)
+	}
+	return lines
+}
@@ -1,5 +1,10 @@
 package metar
 
+import (
+	"github.com/cragcraig/flight/geo"
+	"time"
+)
+
 const feet_per_meter = 3.28084
 
 type SkyCondition struct {
@@ -7,7 +12,7 @@ type SkyCondition struct {
 	CloudBaseAgl string `xml:"cloud_base_ft_agl,attr"`
 }
 
-// XML fields documented at https://aviationweather.gov/adds/dataserver/metars/MetarFieldDescription.php
+// XML fields documented at https://aviationweather.gov/data/api/
 type Metar struct {
 	RawText         string         `xml:"raw_text"`
 	StationId       string         `xml:"station_id"`
@@ -33,3 +38,11 @@ func (m Metar) String() string {
 func (m Metar) AltInFt() int {
 	return int(m.Elevation * feet_per_meter)
 }
+
+func (m Metar) Coord() geo.Coord {
+	return geo.NewCoord(m.Latitude, m.Longitude)
+}
+
+func (m Metar) ObservedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, m.ObservationTime)
+}
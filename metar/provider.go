@@ -0,0 +1,18 @@
+package metar
+
+import (
+	"github.com/cragcraig/flight/geo"
+)
+
+// A source of METAR observations
+type Provider interface {
+	// Most recent observation for each station within the time window
+	QueryStations(stations []string, hoursBeforeNow float64) ([]Metar, error)
+	// Every observation for each station within the time window
+	QueryHistory(stations []string, hoursBeforeNow float64) ([]Metar, error)
+	// Most recent observation for each station within radius statute miles
+	QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Metar, error)
+}
+
+// Provider used by the package level query functions
+var DefaultProvider Provider = NewAviationWeather()
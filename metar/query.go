@@ -1,31 +1,21 @@
 package metar
 
 import (
-	"encoding/xml"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
+	"github.com/cragcraig/flight/aviationweather"
 )
 
 type query struct {
-	Errors []string `xml:"errors>error"`
-	Metar  []Metar  `xml:"data>METAR"`
+	aviationweather.Response
+	Metar []Metar `xml:"data>METAR"`
 }
 
-// Parses the result of a query to https://aviationweather.gov/adds/dataserver
-// e.g., https://aviationweather.gov/adds/dataserver_current/httpparam?dataSource=metars&requestType=retrieve&format=xml&stationString=KBDU&hoursBeforeNow=3&mostRecent=true
+// Parses the XML result of a query to https://aviationweather.gov/api/data/metar
+// e.g., https://aviationweather.gov/api/data/metar?ids=KBDU&format=xml&hours=3
 func unmarshalXml(xmlBody []byte) ([]Metar, error) {
 	var q query
-	err := xml.Unmarshal(xmlBody, &q)
-	if err != nil {
-		return []Metar{}, fmt.Errorf("%s: got \n%s", err, xmlBody)
-	}
-	if len(q.Errors) != 0 {
-		return q.Metar, errors.New("error(s) from METAR service: " + strings.Join(q.Errors, ", "))
+	if err := aviationweather.Unmarshal(xmlBody, "METAR", &q); err != nil {
+		return []Metar{}, err
 	}
 	// Check for obvious bad data
 	for _, m := range q.Metar {
@@ -35,39 +25,3 @@ func unmarshalXml(xmlBody []byte) ([]Metar, error) {
 	}
 	return q.Metar, nil
 }
-
-func fetchContents(queryUrl string) ([]byte, error) {
-	resp, err := http.Get(queryUrl)
-	if err != nil {
-		return []byte{}, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-	return body, nil
-}
-
-func buildQueryUrl(parameters url.Values, hoursBeforeNow float64, mostRecentOnly bool) string {
-	u, err := url.Parse("https://aviationweather.gov/adds/dataserver_current/httpparam")
-	if err != nil {
-		panic("bad base url")
-	}
-	parameters.Add("dataSource", "metars")
-	parameters.Add("requestType", "retrieve")
-	parameters.Add("format", "xml")
-	parameters.Add("hoursBeforeNow", fmt.Sprintf("%.2f", hoursBeforeNow))
-	parameters.Add("mostRecentForEachStation", strconv.FormatBool(mostRecentOnly))
-	u.RawQuery = parameters.Encode()
-	return u.String()
-}
-
-func queryMetars(parameters url.Values, hoursBeforeNow float64, mostRecentOnly bool) ([]Metar, error) {
-	queryUrl := buildQueryUrl(parameters, hoursBeforeNow, mostRecentOnly)
-	body, err := fetchContents(queryUrl)
-	if err != nil {
-		return []Metar{}, err
-	}
-	return unmarshalXml(body)
-}
@@ -2,20 +2,30 @@ package metar
 
 import (
 	"errors"
-	"net/url"
-	"strings"
 )
 
-func QueryStations(stations []string, hoursBeforeNow float64, mostRecentOnly bool) ([]Metar, error) {
+func validateStations(stations []string) error {
 	// stations should be 3 or 4 characters
 	for _, s := range stations {
 		if len(s) != 3 && len(s) != 4 {
-			return []Metar{}, errors.New("station ids must be valid station identifiers: " + s)
+			return errors.New("station ids must be valid station identifiers: " + s)
 		}
 	}
+	return nil
+}
 
-	parameters := url.Values{}
-	parameters.Add("stationString", strings.Join(stations, ","))
+// Most recent METAR for each station
+func QueryStations(stations []string, hoursBeforeNow float64) ([]Metar, error) {
+	if err := validateStations(stations); err != nil {
+		return []Metar{}, err
+	}
+	return DefaultProvider.QueryStations(stations, hoursBeforeNow)
+}
 
-	return queryMetars(parameters, hoursBeforeNow, mostRecentOnly)
+// All METARs for each station
+func QueryHistory(stations []string, hoursBeforeNow float64) ([]Metar, error) {
+	if err := validateStations(stations); err != nil {
+		return []Metar{}, err
+	}
+	return DefaultProvider.QueryHistory(stations, hoursBeforeNow)
 }
@@ -0,0 +1,44 @@
+package parse
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/geo"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Compass degrees with an optional true or magnetic suffix, true if omitted
+// e.g., 310, 310T, 310M
+func ParseHeading(s string) (float64, geo.North, error) {
+	deg, north := splitNorth(s)
+	v, err := strconv.ParseFloat(deg, 64)
+	if err != nil || v < 0 || v > 360 {
+		return 0, geo.TrueNorth, errors.New("Invalid heading, expected degrees such as 310, 310T or 310M: " + s)
+	}
+	return v, north, nil
+}
+
+// Wind vector as in ParseGeoVect, with an optional suffix on the direction
+// e.g., 12@270, 12@270M
+func ParseWind(s string) (geo.Vect, geo.North, error) {
+	v, north := splitNorth(s)
+	if !strings.ContainsRune(v, '@') {
+		return geo.Vect{}, geo.TrueNorth, errors.New("Invalid wind, expected SPEED@DIRECTION: " + s)
+	}
+	wind, err := parseDirOffset(v)
+	return wind, north, err
+}
+
+func splitNorth(s string) (string, geo.North) {
+	if len(s) == 0 {
+		return s, geo.TrueNorth
+	}
+	switch unicode.ToUpper(rune(s[len(s)-1])) {
+	case 'M':
+		return s[:len(s)-1], geo.MagneticNorth
+	case 'T':
+		return s[:len(s)-1], geo.TrueNorth
+	}
+	return s, geo.TrueNorth
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/cragcraig/flight/geo"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -15,10 +16,16 @@ import (
 // 45.42,-105.03
 // KBDU
 // BJC
+// BDU or PANC, either the FAA or ICAO identifier of a landing facility
 // KBDU+5N+3W
 // KBDU+8@340
 // KBDU+10W+8@340
 // 45.42,-105.03+5N+3W
+// 40°02'21"N 105°13'34"W
+// N40 02.35 W105 13.57+5N
+// 400221N1051334W
+// 13T 476543 4431234
+// 13TDE7654331234+8@340
 func ParsePos(natfix data.Natfix, pos string) (geo.Coord, error) {
 	if len(pos) == 0 {
 		return geo.ErrCoord(), errors.New("empty position string")
@@ -42,21 +49,41 @@ func ParsePos(natfix data.Natfix, pos string) (geo.Coord, error) {
 	}
 }
 
-// A string containing a Lat,Lon or station id
+// A string containing a coordinate, see geo.ParseCoord, or station id
 func parseStart(natfix data.Natfix, pos string) (geo.Coord, error) {
-	if strings.ContainsRune(pos, ',') {
-		// Lon,Lat coordinate
-		if c, err := geo.ParseLatLon(pos); err != nil {
-			return c, err
-		} else {
-			return c, nil
-		}
-	} else if c, err := natfix.GetFix(pos); err != nil {
+	if c, err := geo.ParseCoord(pos); err == nil {
+		return c, nil
+	} else if strings.ContainsAny(pos, ", ") {
+		// Station ids never contain separators
 		return geo.ErrCoord(), err
-	} else {
+	} else if c, err := natfix.GetFix(pos); err == nil {
 		// Station position
 		return c, nil
+	} else if apt, aptErr := getApt(pos); aptErr == nil {
+		// Landing facility NATFIX doesn't list by this identifier
+		return apt.Coord, nil
+	} else {
+		return geo.ErrCoord(), err
+	}
+}
+
+// The APT database is large, so it's only loaded when NATFIX lacks a station,
+// and then only once
+var (
+	loadApts = data.LoadApts
+	aptsOnce sync.Once
+	apts     data.Apts
+	aptsErr  error
+)
+
+func getApt(station string) (data.Apt, error) {
+	aptsOnce.Do(func() {
+		apts, aptsErr = loadApts()
+	})
+	if aptsErr != nil {
+		return data.Apt{}, aptsErr
 	}
+	return apts.GetApt(station)
 }
 
 // e.g., 5N 3W 23@340
@@ -68,27 +95,28 @@ func ParseGeoVect(v string) (geo.Vect, error) {
 	} else if strings.ContainsRune(v, '@') {
 		// Relative directional vector
 		// e.g., KBDU+4@340
-		// Makes flat-earth assumption, not valid over large distances
 		return parseDirOffset(v)
 	} else {
 		return geo.Vect{}, errors.New("Invalid vector: " + v)
 	}
 }
 
-// Apply position relative modifiers
+// Apply position relative modifiers, in order, as geodesic moves
 // e.g., +5N +3W +23@340
 func parseAndApplyModifiers(c geo.Coord, modifiers []string) (geo.Coord, error) {
 	for _, m := range modifiers {
 		if v, err := ParseGeoVect(m); err != nil {
 			return geo.ErrCoord(), err
-		} else {
-			c = c.AddToLon(v.X).AddToLat(v.Y)
+		} else if v.IsOrigin() {
+			continue
+		} else if c, err = geo.Destination(c, geo.Rad2Compass(v.AsAngle()), v.Magnitude()); err != nil {
+			return geo.ErrCoord(), errors.New("Invalid position modifier " + m + ": " + err.Error())
 		}
 	}
 	return c, nil
 }
 
-// e.g., 23N 3W etc
+// e.g., 26@340
 func parseDirOffset(s string) (geo.Vect, error) {
 	var v, dir float64
 	_, err := fmt.Sscanf(s, "%f@%f", &v, &dir)
@@ -100,11 +128,10 @@ func parseDirOffset(s string) (geo.Vect, error) {
 	return geo.HeadingFromAngle(theta).Mult(v), nil
 }
 
-// e.g., 26@340
+// e.g., 23N 3W etc
 func parsePosOffset(s string) (geo.Vect, error) {
 	if len(s) < 2 {
-		// Unreachable
-		panic("vector too short: " + s)
+		return geo.Vect{}, errors.New("Invalid cardinal vector: " + s)
 	}
 	v, err := strconv.ParseFloat(s[:len(s)-1], 64)
 	if err != nil {
@@ -112,13 +139,13 @@ func parsePosOffset(s string) (geo.Vect, error) {
 	}
 	dir := unicode.ToUpper(rune(s[len(s)-1]))
 	if dir == 'N' {
-		return geo.Vect{0, v}, nil
+		return geo.Vect{X: 0, Y: v}, nil
 	} else if dir == 'S' {
-		return geo.Vect{0, -1 * v}, nil
+		return geo.Vect{X: 0, Y: -1 * v}, nil
 	} else if dir == 'E' {
-		return geo.Vect{v, 0}, nil
+		return geo.Vect{X: v, Y: 0}, nil
 	} else if dir == 'W' {
-		return geo.Vect{-1 * v, 0}, nil
+		return geo.Vect{X: -1 * v, Y: 0}, nil
 	}
 	return geo.Vect{}, errors.New("Invalid cardinal direction: " + s)
 }
@@ -0,0 +1,72 @@
+package parse
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"sync"
+	"testing"
+)
+
+func TestGetAptLoadsOnce(t *testing.T) {
+	defer func(load func() (data.Apts, error)) {
+		loadApts, aptsOnce = load, sync.Once{}
+	}(loadApts)
+	loads := 0
+	loadApts = func() (data.Apts, error) {
+		loads++
+		return data.Apts{}, errors.New("No APT database")
+	}
+	aptsOnce = sync.Once{}
+
+	for _, station := range []string{"KXXX", "KYYY", "KXXX"} {
+		if _, err := ParsePos(data.Natfix{}, station); err == nil {
+			t.Errorf("%s: expected an error for an unknown station", station)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Loaded the APT database %d times, want once", loads)
+	}
+}
+
+func TestParsePosCoordinates(t *testing.T) {
+	boulder := geo.NewCoord(40.0392, -105.2261)
+	north, err := geo.Destination(boulder, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		pos       string
+		want      geo.Coord
+		tolerance float64 // NM
+	}{
+		{"40.0392,-105.2261", boulder, 1e-6},
+		{`40°02'21.1"N 105°13'34.0"W`, boulder, 0.01},
+		{"N40 02.35 W105 13.57", boulder, 0.01},
+		{"400221N1051334W", boulder, 0.01},
+		{"13T 480710 4432132", boulder, 0.01},
+		{"13TDE8071032131", boulder, 0.01},
+		{"-33.8568,151.2153", geo.NewCoord(-33.8568, 151.2153), 1e-6},
+		{"S33 51.41 E151 12.92", geo.NewCoord(-33.8568, 151.2153), 0.01},
+		{"335124S1511255E", geo.NewCoord(-33.8568, 151.2153), 0.01},
+		{"56H 334901 6252288", geo.NewCoord(-33.8568, 151.2153), 0.01},
+		// Modifiers apply to every format
+		{"40.0392,-105.2261+5N", north, 1e-6},
+		{"N40 02.35 W105 13.57+5N", north, 0.01},
+		{"400221N1051334W+5N", north, 0.01},
+		{"13TDE8071032131+5@360", north, 0.01},
+	} {
+		got, err := ParsePos(data.Natfix{}, c.pos)
+		if err != nil {
+			t.Errorf("%s: %s", c.pos, err)
+		} else if d := geo.DistanceNM(got, c.want); d > c.tolerance {
+			t.Errorf("%s: got %s, %.3f NM from %s", c.pos, got, d, c.want)
+		}
+	}
+
+	for _, pos := range []string{"N40 02.35", "40 02 21 105 13 34", "13T 480710", "N40 02.35 W105 13.57+5X"} {
+		if c, err := ParsePos(data.Natfix{}, pos); err == nil {
+			t.Errorf("%s: got %s, want an error", pos, c)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package parse
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"strings"
+)
+
+// A point along a route, and the airway flown to reach it
+type RoutePoint struct {
+	Name    string
+	Pos     geo.Coord
+	Airway  string              // Empty if flown direct
+	Segment *data.AirwaySegment // Airway segment ending here in the direction flown, nil if direct
+}
+
+type Route []RoutePoint
+
+// Parse a route string of positions, see ParsePos, joined by airways
+// entered and exited at fixes on them, e.g.,
+// KBDU DVV V8 AKO KCYS
+// KBDU BJC+5N KCYS
+// Each airway is expanded into its individual points.
+func ParseRoute(natfix data.Natfix, airways data.Airways, route string) (Route, error) {
+	tokens := strings.Fields(route)
+	if len(tokens) < 2 {
+		return nil, errors.New("Route requires at least two positions: " + route)
+	}
+	r := Route{}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if i != 0 && i != len(tokens)-1 && airways.IsAirway(t) {
+			legs, err := airways.Expand(t, tokens[i-1], tokens[i+1])
+			if err != nil {
+				return nil, err
+			}
+			for _, l := range legs {
+				seg := l.Segment
+				r = append(r, RoutePoint{Name: l.To.Id, Pos: l.To.Coord, Airway: strings.ToUpper(t), Segment: &seg})
+			}
+			// The exit fix was consumed by the airway
+			i++
+		} else if pos, err := ParsePos(natfix, t); err != nil {
+			return nil, err
+		} else {
+			r = append(r, RoutePoint{Name: strings.ToUpper(t), Pos: pos})
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+package pirep
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/aviationweather"
+	"github.com/cragcraig/flight/geo"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const aviationWeatherUrl = "https://aviationweather.gov/api/data/pirep"
+
+// Provider backed by the aviationweather.gov data API
+// See https://aviationweather.gov/data/api/
+type AviationWeather struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+func NewAviationWeather() AviationWeather {
+	return AviationWeather{
+		BaseUrl: aviationWeatherUrl,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (a AviationWeather) QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Pirep, error) {
+	// The API only supports rectangular queries, so filter the corners afterwards
+	nm := geo.StatuteMiles2NM(float64(radius))
+	sw, ne := geo.BoundingBox(coord, nm)
+	parameters := url.Values{}
+	parameters.Add("bbox", fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", sw.Lat(), sw.Lon(), ne.Lat(), ne.Lon()))
+	parameters.Add("age", fmt.Sprintf("%.2f", hoursBeforeNow))
+	parameters.Add("format", "xml")
+	u, err := url.Parse(a.BaseUrl)
+	if err != nil {
+		panic("bad base url: " + a.BaseUrl)
+	}
+	u.RawQuery = parameters.Encode()
+
+	body, err := aviationweather.Fetch(a.Client, u.String(), "PIREP")
+	if err != nil {
+		return []Pirep{}, err
+	}
+	if len(body) == 0 {
+		// No matching reports
+		return []Pirep{}, nil
+	}
+	pireps, err := unmarshalXml(body)
+	if err != nil {
+		return []Pirep{}, err
+	}
+	within := []Pirep{}
+	for _, p := range pireps {
+		if geo.GlobeDistNM(coord, p.Coord()) <= nm {
+			within = append(within, p)
+		}
+	}
+	return within, nil
+}
@@ -0,0 +1,73 @@
+package pirep
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Recorded from the data API, trimmed to the fields used
+const pirepResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<response>
+  <data num_results="2">
+    <AircraftReport>
+      <raw_text>DEN UA /OV BJC/TM 1810/FL090/TP C172/TB LGT</raw_text>
+      <observation_time>2026-10-18T18:10:00Z</observation_time>
+      <latitude>39.9089</latitude>
+      <longitude>-105.1172</longitude>
+      <altitude_ft_msl>9000</altitude_ft_msl>
+      <report_type>PIREP</report_type>
+    </AircraftReport>
+    <AircraftReport>
+      <raw_text>COS UA /OV COS/TM 1805/FL110/TP PA28/SK BKN080</raw_text>
+      <observation_time>2026-10-18T18:05:00Z</observation_time>
+      <latitude>38.8058</latitude>
+      <longitude>-104.7008</longitude>
+      <altitude_ft_msl>11000</altitude_ft_msl>
+      <report_type>PIREP</report_type>
+    </AircraftReport>
+  </data>
+</response>`
+
+func serve(t *testing.T, status int, body string) (AviationWeather, *url.Values) {
+	query := &url.Values{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*query = r.URL.Query()
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(s.Close)
+	a := NewAviationWeather()
+	a.BaseUrl = s.URL
+	a.Client = s.Client()
+	return a, query
+}
+
+func TestQueryRadius(t *testing.T) {
+	a, query := serve(t, http.StatusOK, pirepResponse)
+	// KCOS is about 80 statute miles from KBJC
+	pireps, err := a.QueryRadius(geo.NewCoord(39.9089, -105.1172), 30, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("bbox") == "" || query.Get("age") != "1.50" || query.Get("format") != "xml" {
+		t.Errorf("Query: got %v", *query)
+	}
+	if len(pireps) != 1 || pireps[0].AltitudeFtMsl != 9000 {
+		t.Errorf("Got %v, want only the report over BJC", pireps)
+	}
+}
+
+func TestQueryRadiusErrors(t *testing.T) {
+	a, _ := serve(t, http.StatusOK, "<response><errors><error>Invalid bbox</error></errors></response>")
+	if _, err := a.QueryRadius(geo.NewCoord(39.9, -105.1), 30, 1); err == nil {
+		t.Error("Expected the service's error")
+	}
+	a, _ = serve(t, http.StatusNoContent, "")
+	if pireps, err := a.QueryRadius(geo.NewCoord(39.9, -105.1), 30, 1); err != nil || len(pireps) != 0 {
+		t.Errorf("No content: got %v, %v", pireps, err)
+	}
+}
@@ -0,0 +1,175 @@
+package pirep
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Typed representation of a raw UA or UUA pilot report
+type Report struct {
+	Station         string // Reporting station, if present
+	Urgent          bool   // UUA rather than UA
+	Location        string // /OV, e.g., DEN090025
+	Hour, Minute    int    // /TM, -1 if not reported
+	FlightLevel     string // /FL, e.g., 080, UNKN, DURC
+	AltitudeFt      int    // Decoded /FL, -1 if unknown
+	AircraftType    string // /TP
+	Sky             string // /SK
+	Weather         string // /WX
+	TempC           *int   // /TA
+	WindDir         int    // /WV, true degrees
+	WindSpeed       int    // /WV, knots, -1 if not reported
+	Turbulence      string // /TB
+	TurbulenceLevel int    // See Turbulence*, -1 if not reported
+	Icing           string // /IC
+	IcingLevel      int    // See Icing*, -1 if not reported
+	Remarks         string // /RM
+}
+
+// Turbulence intensities, in increasing severity
+const (
+	TurbulenceNone = iota
+	TurbulenceLight
+	TurbulenceModerate
+	TurbulenceSevere
+	TurbulenceExtreme
+)
+
+// Icing intensities, in increasing severity
+const (
+	IcingNone = iota
+	IcingTrace
+	IcingLight
+	IcingModerate
+	IcingSevere
+)
+
+var turbulenceWords = map[string]int{
+	"NEG":    TurbulenceNone,
+	"SMTH":   TurbulenceNone,
+	"SMOOTH": TurbulenceNone,
+	"LGT":    TurbulenceLight,
+	"MOD":    TurbulenceModerate,
+	"SEV":    TurbulenceSevere,
+	"EXTRM":  TurbulenceExtreme,
+	"EXTM":   TurbulenceExtreme,
+}
+
+var icingWords = map[string]int{
+	"NEG":    IcingNone,
+	"NEGCLR": IcingNone,
+	"TRACE":  IcingTrace,
+	"TRC":    IcingTrace,
+	"LGT":    IcingLight,
+	"MOD":    IcingModerate,
+	"SEV":    IcingSevere,
+}
+
+var (
+	headerRegexp = regexp.MustCompile(`^(?:([A-Z0-9]{3,4})\s+)?(UUA|UA)\b`)
+	// FL is followed directly by the level, e.g., /FL080, other codes by a
+	// space, so /OVC080 within a sky layer isn't a field
+	fieldRegexp   = regexp.MustCompile(`/(FL|(?:OV|TM|TP|SK|WX|TA|WV|TB|IC|RM)\b)\s*`)
+	tmRegexp      = regexp.MustCompile(`^(\d{2})(\d{2})$`)
+	flRegexp      = regexp.MustCompile(`^(\d{3})$`)
+	taRegexp      = regexp.MustCompile(`^(M|-)?(\d{1,2})$`)
+	wvRegexp      = regexp.MustCompile(`^(\d{3})(\d{2,3})(KT)?$`)
+	intensityWord = regexp.MustCompile(`[A-Z]+`)
+)
+
+// Decodes a raw pilot report, e.g.,
+// DEN UA /OV DEN090025/TM 1815/FL080/TP C172/TA M05/WV 27045KT/TB MOD 060-080/IC LGT RIME 070/RM SMOOTH ABV 090
+func Decode(raw string) (Report, error) {
+	text := strings.TrimSpace(strings.ToUpper(raw))
+	m := headerRegexp.FindStringSubmatch(text)
+	if m == nil {
+		return Report{}, errors.New("PIREP is missing a UA or UUA report type: " + raw)
+	}
+	r := Report{
+		Station:         m[1],
+		Urgent:          m[2] == "UUA",
+		Hour:            -1,
+		Minute:          -1,
+		AltitudeFt:      -1,
+		WindSpeed:       -1,
+		TurbulenceLevel: -1,
+		IcingLevel:      -1,
+	}
+
+	fields := fieldRegexp.FindAllStringSubmatchIndex(text, -1)
+	if len(fields) == 0 {
+		return Report{}, errors.New("PIREP contains no fields: " + raw)
+	}
+	for i, f := range fields {
+		end := len(text)
+		if i+1 < len(fields) {
+			end = fields[i+1][0]
+		}
+		key := text[f[2]:f[3]]
+		value := strings.TrimSpace(text[f[1]:end])
+		switch key {
+		case "OV":
+			r.Location = value
+		case "TM":
+			if m := tmRegexp.FindStringSubmatch(value); m != nil {
+				r.Hour, _ = strconv.Atoi(m[1])
+				r.Minute, _ = strconv.Atoi(m[2])
+			}
+		case "FL":
+			r.FlightLevel = value
+			if m := flRegexp.FindStringSubmatch(value); m != nil {
+				fl, _ := strconv.Atoi(m[1])
+				r.AltitudeFt = fl * 100
+			}
+		case "TP":
+			r.AircraftType = value
+		case "SK":
+			r.Sky = value
+		case "WX":
+			r.Weather = value
+		case "TA":
+			if m := taRegexp.FindStringSubmatch(value); m != nil {
+				t, _ := strconv.Atoi(m[2])
+				if m[1] != "" {
+					t = -t
+				}
+				r.TempC = &t
+			}
+		case "WV":
+			if m := wvRegexp.FindStringSubmatch(value); m != nil {
+				r.WindDir, _ = strconv.Atoi(m[1])
+				r.WindSpeed, _ = strconv.Atoi(m[2])
+			}
+		case "TB":
+			r.Turbulence = value
+			r.TurbulenceLevel = maxIntensity(value, turbulenceWords)
+		case "IC":
+			r.Icing = value
+			r.IcingLevel = maxIntensity(value, icingWords)
+		case "RM":
+			r.Remarks = value
+		}
+	}
+	return r, nil
+}
+
+// Highest intensity word within a field, e.g., LGT-MOD CHOP is moderate
+func maxIntensity(value string, words map[string]int) int {
+	level := -1
+	for _, w := range intensityWord.FindAllString(value, -1) {
+		if l, exists := words[w]; exists && l > level {
+			level = l
+		}
+	}
+	return level
+}
+
+func (r Report) HasTurbulence() bool {
+	return r.TurbulenceLevel > TurbulenceNone
+}
+
+func (r Report) HasIcing() bool {
+	return r.IcingLevel > IcingNone
+}
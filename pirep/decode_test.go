@@ -0,0 +1,104 @@
+package pirep
+
+import (
+	"testing"
+)
+
+func TestDecodeRoutine(t *testing.T) {
+	r, err := Decode("DEN UA /OV DEN090025/TM 1815/FL080/TP C172/TA M05/WV 27045KT/TB MOD 060-080/IC LGT RIME 070/RM SMOOTH ABV 090")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Station != "DEN" || r.Urgent {
+		t.Errorf("Header: got station %q, urgent %v", r.Station, r.Urgent)
+	}
+	if r.Location != "DEN090025" {
+		t.Errorf("Location: got %q", r.Location)
+	}
+	if r.Hour != 18 || r.Minute != 15 {
+		t.Errorf("Time: got %d:%d", r.Hour, r.Minute)
+	}
+	if r.FlightLevel != "080" || r.AltitudeFt != 8000 {
+		t.Errorf("Flight level: got %q, %d ft", r.FlightLevel, r.AltitudeFt)
+	}
+	if r.AircraftType != "C172" {
+		t.Errorf("Aircraft type: got %q", r.AircraftType)
+	}
+	if r.TempC == nil || *r.TempC != -5 {
+		t.Errorf("Temperature: got %v", r.TempC)
+	}
+	if r.WindDir != 270 || r.WindSpeed != 45 {
+		t.Errorf("Wind: got %d@%d", r.WindSpeed, r.WindDir)
+	}
+	if r.TurbulenceLevel != TurbulenceModerate || !r.HasTurbulence() {
+		t.Errorf("Turbulence: got %q, level %d", r.Turbulence, r.TurbulenceLevel)
+	}
+	if r.IcingLevel != IcingLight || !r.HasIcing() {
+		t.Errorf("Icing: got %q, level %d", r.Icing, r.IcingLevel)
+	}
+	if r.Remarks != "SMOOTH ABV 090" {
+		t.Errorf("Remarks: got %q", r.Remarks)
+	}
+}
+
+func TestDecodeUrgent(t *testing.T) {
+	r, err := Decode("COS UUA /OV COS180010/TM 2102/FL110/TP PA28/TB SEV/RM LLWS -15 KT SFC-003 DURD RWY17R")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Station != "COS" || !r.Urgent {
+		t.Errorf("Header: got station %q, urgent %v", r.Station, r.Urgent)
+	}
+	if r.TurbulenceLevel != TurbulenceSevere {
+		t.Errorf("Turbulence: got %q, level %d", r.Turbulence, r.TurbulenceLevel)
+	}
+	if r.IcingLevel != -1 || r.HasIcing() {
+		t.Errorf("Icing: got level %d, want unreported", r.IcingLevel)
+	}
+	if r.WindSpeed != -1 || r.TempC != nil {
+		t.Errorf("Unreported wind and temperature: got %d kts, %v", r.WindSpeed, r.TempC)
+	}
+}
+
+func TestDecodeMultipleSkyLayers(t *testing.T) {
+	r, err := Decode("DEN UA /OV DEN090030/TM 1830/FL080/TP C172/SK BKN040-TOP060/OVC080/TB MOD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Location != "DEN090030" {
+		t.Errorf("Location: got %q", r.Location)
+	}
+	if r.Sky != "BKN040-TOP060/OVC080" {
+		t.Errorf("Sky: got %q", r.Sky)
+	}
+	if r.TurbulenceLevel != TurbulenceModerate {
+		t.Errorf("Turbulence: got %q, level %d", r.Turbulence, r.TurbulenceLevel)
+	}
+}
+
+func TestDecodeIntensityRanges(t *testing.T) {
+	r, err := Decode("UA /OV BJC/TM 0012/FL DURC/TP B737/TB LGT-MOD CHOP/IC NEG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Station != "" {
+		t.Errorf("Station: got %q, want none", r.Station)
+	}
+	if r.FlightLevel != "DURC" || r.AltitudeFt != -1 {
+		t.Errorf("Flight level: got %q, %d ft", r.FlightLevel, r.AltitudeFt)
+	}
+	if r.TurbulenceLevel != TurbulenceModerate {
+		t.Errorf("Turbulence: got %q, level %d", r.Turbulence, r.TurbulenceLevel)
+	}
+	if r.IcingLevel != IcingNone || r.HasIcing() {
+		t.Errorf("Icing: got %q, level %d", r.Icing, r.IcingLevel)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "DEN /OV DEN090025/TM 1815", "DEN UA"} {
+		if _, err := Decode(raw); err == nil {
+			t.Errorf("Decode(%q): expected an error", raw)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pirep
+
+import (
+	"github.com/cragcraig/flight/geo"
+	"time"
+)
+
+// XML fields documented at https://aviationweather.gov/data/api/
+type Pirep struct {
+	RawText         string  `xml:"raw_text"`
+	ReceiptTime     string  `xml:"receipt_time"`
+	ObservationTime string  `xml:"observation_time"`
+	AircraftRef     string  `xml:"aircraft_ref"`
+	Longitude       float64 `xml:"longitude"`
+	Latitude        float64 `xml:"latitude"`
+	AltitudeFtMsl   int     `xml:"altitude_ft_msl"`
+	ReportType      string  `xml:"report_type"`
+}
+
+func (p Pirep) String() string {
+	return p.RawText
+}
+
+func (p Pirep) Coord() geo.Coord {
+	return geo.NewCoord(p.Latitude, p.Longitude)
+}
+
+func (p Pirep) ObservedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, p.ObservationTime)
+}
+
+func (p Pirep) Decode() (Report, error) {
+	return Decode(p.RawText)
+}
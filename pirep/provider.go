@@ -0,0 +1,14 @@
+package pirep
+
+import (
+	"github.com/cragcraig/flight/geo"
+)
+
+// A source of pilot reports
+type Provider interface {
+	// Reports within radius statute miles received within the time window
+	QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Pirep, error)
+}
+
+// Provider used by the package level query functions
+var DefaultProvider Provider = NewAviationWeather()
@@ -0,0 +1,40 @@
+package pirep
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/aviationweather"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+)
+
+type query struct {
+	aviationweather.Response
+	Pireps []Pirep `xml:"data>AircraftReport"`
+}
+
+// Parses the XML result of a query to https://aviationweather.gov/api/data/pirep
+// e.g., https://aviationweather.gov/api/data/pirep?bbox=39,-106,41,-104&age=3&format=xml
+func unmarshalXml(xmlBody []byte) ([]Pirep, error) {
+	var q query
+	if err := aviationweather.Unmarshal(xmlBody, "PIREP", &q); err != nil {
+		return []Pirep{}, err
+	}
+	return q.Pireps, nil
+}
+
+// PIREPs within radius statute miles
+func QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Pirep, error) {
+	if radius < 0 || radius > 500 {
+		return []Pirep{}, errors.New("radius must be between 0 and 500 miles")
+	}
+	return DefaultProvider.QueryRadius(coord, radius, hoursBeforeNow)
+}
+
+func QueryStationRadius(natfix data.Natfix, station string, radius int, hoursBeforeNow float64) ([]Pirep, error) {
+	c, err := parse.ParsePos(natfix, station)
+	if err != nil {
+		return []Pirep{}, err
+	}
+	return QueryRadius(c, radius, hoursBeforeNow)
+}
@@ -0,0 +1,273 @@
+package plan
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Flight plan (.flgt) file format
+//
+// A line oriented text format. Blank lines and lines starting with '#' are
+// ignored. The first record is the format header:
+//
+//	FLGT <version>
+//
+// followed by one record per waypoint, in order of flight:
+//
+//	<KIND> <NAME> <LAT,LON> <ALT> <FPM> <RPM>
+//
+// KIND is ORIGIN for the first waypoint, DEST for the last and WPT for every
+// waypoint in between. NAME is the airport identifier or position as entered
+// by the user, quoted if it contains whitespace. ALT is the planned altitude
+// over the waypoint in feet MSL, while FPM and RPM are the climb (or descent)
+// rate and power setting used when leaving the waypoint.
+//
+// Since version 3, a waypoint in between may instead be a computed TOC (top of
+// climb) or TOD (top of descent). These are named FIX/NM for their distance
+// after the fix a climb began at, or before the fix a descent ends at.
+//
+// Since version 2, a waypoint record may be followed by weather records that
+// annotate it, conventionally indented. Winds and temperature aloft apply to
+// the leg departing the waypoint, or arriving for the destination:
+//
+//	ETA <RFC 3339 time>
+//	WIND <SPEED@DIRECTION>   winds aloft along the leg, true direction
+//	TEMP <CELSIUS>           temperature aloft along the leg
+//	VAR <DEGREES>            magnetic variation, west positive
+//	METAR <QUOTED RAW TEXT>
+//	TAF <QUOTED RAW TEXT>
+//
+// e.g.,
+//
+//	FLGT 3
+//	ORIGIN KBDU 40.039389,-105.225806 5288 700 2500
+//	  ETA 2026-10-18T18:00:00Z
+//	  VAR -8
+//	  METAR "KBDU 181753Z AUTO 15006KT 10SM CLR 18/M03 A2984"
+//	TOC KBDU/6.2 39.962861,-105.172155 9000 0 2500
+//	WPT BJC 39.913056,-105.138889 9000 500 2400
+//	  WIND 23@120
+//	  TEMP -2
+//	DEST KCOS 38.805805,-104.700778 6187 0 0
+const FormatVersion = 3
+
+// Weather record types
+const (
+	etaRecord   = "ETA"
+	windRecord  = "WIND"
+	tempRecord  = "TEMP"
+	varRecord   = "VAR"
+	metarRecord = "METAR"
+	tafRecord   = "TAF"
+)
+
+const headerRecord = "FLGT"
+
+// Serializes the plan using the current format version
+func (p Plan) Write(w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %d\n", headerRecord, FormatVersion)
+	for _, wp := range p.Waypoints {
+		fmt.Fprintf(bw, "%s %s %.6f,%.6f %d %d %d\n",
+			wp.Kind, quoteField(wp.Name), wp.Pos.Lat(), wp.Pos.Lon(), wp.Alt, wp.Fpm, wp.Rpm)
+		writeWeather(bw, wp.Wx)
+	}
+	return bw.Flush()
+}
+
+func writeWeather(w io.Writer, wx Weather) {
+	if wx.Eta != nil {
+		fmt.Fprintf(w, "  %s %s\n", etaRecord, wx.Eta.UTC().Format(time.RFC3339))
+	}
+	if wx.Wind != nil {
+		fmt.Fprintf(w, "  %s %d@%d\n", windRecord,
+			int(math.Round(wx.Wind.Magnitude())),
+			int(math.Round(geo.Rad2Compass(wx.Wind.AsAngle()))))
+	}
+	if wx.Temp != nil {
+		fmt.Fprintf(w, "  %s %.0f\n", tempRecord, *wx.Temp)
+	}
+	if wx.Variation != nil {
+		fmt.Fprintf(w, "  %s %d\n", varRecord, *wx.Variation)
+	}
+	if len(wx.Metar) != 0 {
+		fmt.Fprintf(w, "  %s %s\n", metarRecord, strconv.Quote(wx.Metar))
+	}
+	if len(wx.Taf) != 0 {
+		fmt.Fprintf(w, "  %s %s\n", tafRecord, strconv.Quote(wx.Taf))
+	}
+}
+
+// Parses a plan written in any supported format version
+func Read(r io.Reader) (Plan, error) {
+	p := Plan{}
+	version := 0
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		l := strings.TrimSpace(s.Text())
+		if len(l) == 0 || l[0] == '#' {
+			continue
+		}
+		fields, err := splitFields(l)
+		if err != nil {
+			return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s", n, err)
+		}
+		if version == 0 {
+			if version, err = parseHeader(fields); err != nil {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s", n, err)
+			}
+			continue
+		}
+		switch fields[0] {
+		case Origin, Enroute, Dest, Toc, Tod:
+			if (fields[0] == Toc || fields[0] == Tod) && version < 3 {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s requires format version 3", n, fields[0])
+			} else if w, err := parseWaypoint(fields); err != nil {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s", n, err)
+			} else {
+				p.Waypoints = append(p.Waypoints, w)
+			}
+		case etaRecord, windRecord, tempRecord, varRecord, metarRecord, tafRecord:
+			if version < 2 {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s requires format version 2", n, fields[0])
+			} else if len(p.Waypoints) == 0 {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s must follow a waypoint", n, fields[0])
+			} else if err := parseWeather(&p.Waypoints[len(p.Waypoints)-1].Wx, fields); err != nil {
+				return Plan{}, fmt.Errorf("Error parsing flight plan line %d: %s", n, err)
+			}
+		default:
+			return Plan{}, fmt.Errorf("Error parsing flight plan line %d: unknown record %s", n, fields[0])
+		}
+	}
+	if err := s.Err(); err != nil {
+		return Plan{}, errors.New("Error reading flight plan: " + err.Error())
+	}
+	if version == 0 {
+		return Plan{}, errors.New("Error parsing flight plan: missing FLGT header")
+	}
+	if err := p.Validate(); err != nil {
+		return Plan{}, err
+	}
+	return p, nil
+}
+
+func parseHeader(fields []string) (int, error) {
+	if len(fields) != 2 || fields[0] != headerRecord {
+		return 0, errors.New("expected FLGT header, got " + strings.Join(fields, " "))
+	}
+	v, err := strconv.Atoi(fields[1])
+	if err != nil || v < 1 {
+		return 0, errors.New("invalid format version " + fields[1])
+	} else if v > FormatVersion {
+		return 0, fmt.Errorf("format version %d is newer than the supported version %d", v, FormatVersion)
+	}
+	return v, nil
+}
+
+func parseWaypoint(fields []string) (Waypoint, error) {
+	if len(fields) != 6 {
+		return Waypoint{}, fmt.Errorf("expected 6 fields in %s record, got %d", fields[0], len(fields))
+	}
+	pos, err := geo.ParseLatLon(fields[2])
+	if err != nil {
+		return Waypoint{}, err
+	}
+	ints := [3]int{}
+	for i, f := range fields[3:] {
+		if ints[i], err = strconv.Atoi(f); err != nil {
+			return Waypoint{}, errors.New("invalid integer " + f)
+		}
+	}
+	return Waypoint{
+		Kind: fields[0],
+		Name: fields[1],
+		Pos:  pos,
+		Alt:  ints[0],
+		Fpm:  ints[1],
+		Rpm:  ints[2],
+	}, nil
+}
+
+func parseWeather(wx *Weather, fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("expected 2 fields in %s record, got %d", fields[0], len(fields))
+	}
+	v := fields[1]
+	switch fields[0] {
+	case etaRecord:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errors.New("invalid ETA " + v)
+		}
+		wx.Eta = &t
+	case windRecord:
+		wind, err := parse.ParseGeoVect(v)
+		if err != nil {
+			return err
+		}
+		wx.Wind = &wind
+	case tempRecord:
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.New("invalid temperature " + v)
+		}
+		wx.Temp = &t
+	case varRecord:
+		variation, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("invalid variation " + v)
+		}
+		wx.Variation = &variation
+	case metarRecord:
+		wx.Metar = v
+	case tafRecord:
+		wx.Taf = v
+	}
+	return nil
+}
+
+func quoteField(s string) string {
+	if len(s) == 0 || strings.IndexFunc(s, unicode.IsSpace) >= 0 || s[0] == '"' {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Whitespace separated fields, where a field may be a Go quoted string
+func splitFields(l string) ([]string, error) {
+	fields := []string{}
+	for {
+		l = strings.TrimLeftFunc(l, unicode.IsSpace)
+		if len(l) == 0 {
+			return fields, nil
+		}
+		if l[0] == '"' {
+			q, err := strconv.QuotedPrefix(l)
+			if err != nil {
+				return nil, errors.New("unterminated quoted field")
+			}
+			v, _ := strconv.Unquote(q)
+			fields = append(fields, v)
+			l = l[len(q):]
+			continue
+		}
+		end := strings.IndexFunc(l, unicode.IsSpace)
+		if end < 0 {
+			end = len(l)
+		}
+		fields = append(fields, l[:end])
+		l = l[end:]
+	}
+}
@@ -0,0 +1,185 @@
+package plan
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"sort"
+	"time"
+)
+
+// Reserves required beyond the trip and alternate fuel, flown at cruise fuel flow
+const (
+	ReserveVfrDay   = 30 * time.Minute
+	ReserveVfrNight = 45 * time.Minute
+	ReserveIfr      = 45 * time.Minute
+)
+
+// Airports further than this from the track are not proposed as fuel stops
+const fuel_stop_corridor_nm = 20
+
+// Spacing of the points sampled along the track to find nearby airports
+const fuel_stop_sample_nm = 5
+
+type LegFuel struct {
+	Leg       LegLog
+	Remaining float64 // On board at the end of the leg
+	Warnings  []string
+}
+
+type FuelPlan struct {
+	OnBoard     float64 // Gallons at engine start
+	Taxi        float64
+	Climb       float64
+	Cruise      float64
+	Descent     float64
+	Trip        float64 // Climb, cruise and descent
+	Alternate   float64
+	Reserve     float64
+	ReserveTime time.Duration
+	Required    float64 // Taxi, trip, alternate and reserve
+	Legs        []LegFuel
+}
+
+// Fuel required to fly the nav log, and then the alternate nav log if not
+// nil, with fuel on board at engine start. Reserves are flown at the cruise
+// fuel flow of the final leg.
+func (n NavLog) FuelPlan(onBoard float64, reserve time.Duration, alternate *NavLog) FuelPlan {
+	f := FuelPlan{
+		OnBoard:     onBoard,
+		Taxi:        math.Min(n.Aircraft.TaxiGal, onBoard),
+		ReserveTime: reserve,
+	}
+	for _, l := range n.Legs {
+		f.Climb += l.Climb.Fuel
+		f.Cruise += l.Cruise.Fuel
+		f.Descent += l.Descent.Fuel
+	}
+	f.Trip = f.Climb + f.Cruise + f.Descent
+	if alternate != nil {
+		f.Alternate = alternate.Total().Fuel
+	}
+	if len(n.Legs) != 0 {
+		f.Reserve = n.Legs[len(n.Legs)-1].Leg.Gph * reserve.Hours()
+	}
+	f.Required = f.Taxi + f.Trip + f.Alternate + f.Reserve
+
+	remaining := onBoard - f.Taxi
+	for _, l := range n.Legs {
+		remaining -= l.Leg.Fuel
+		lf := LegFuel{Leg: l, Remaining: remaining, Warnings: l.Warnings}
+		if remaining < 0 {
+			lf.Warnings = append(lf.Warnings, fmt.Sprintf("Fuel exhausted before %s", l.To.Name))
+		} else if remaining < f.Alternate+f.Reserve {
+			lf.Warnings = append(lf.Warnings, fmt.Sprintf("Arrives at %s with less than the required reserve", l.To.Name))
+		}
+		f.Legs = append(f.Legs, lf)
+	}
+	return f
+}
+
+type FuelStop struct {
+	Apt         data.Apt
+	AlongNM     float64 // Along the route from the origin
+	OffTrackNM  float64
+	ArrivalFuel float64 // Estimated fuel remaining on landing
+}
+
+// Proposes airports near the route to refuel at, each reached with at least
+// the planned reserve and departed with onBoard gallons.
+// Fuel burn is estimated from the nav log by distance along the route, so the
+// result is a starting point for planning the individual legs.
+func (n NavLog) FuelStops(f FuelPlan, apts []data.Apt) ([]FuelStop, error) {
+	// Cumulative distance and fuel at each waypoint
+	dist, fuel := []float64{0}, []float64{0}
+	for _, l := range n.Legs {
+		dist = append(dist, l.Total.Dist)
+		fuel = append(fuel, l.Total.Fuel)
+	}
+	total := dist[len(dist)-1]
+	fuelAt := func(d float64) float64 {
+		i := sort.SearchFloat64s(dist, d)
+		if i == 0 {
+			return 0
+		} else if i == len(dist) {
+			return fuel[len(fuel)-1]
+		}
+		return fuel[i-1] + (d-dist[i-1])/(dist[i]-dist[i-1])*(fuel[i]-fuel[i-1])
+	}
+
+	// Candidate airports near the track, excluding the origin and destination
+	type trackPoint struct {
+		pos   geo.Coord
+		along float64
+	}
+	samples := []trackPoint{}
+	for _, l := range n.Legs {
+		legDist := l.Leg.Dist
+		for d := 0.0; d < legDist; d += fuel_stop_sample_nm {
+			samples = append(samples, trackPoint{
+				pos:   geo.IntermediatePoint(l.From.Pos, l.To.Pos, d/legDist),
+				along: l.Total.Dist - legDist + d,
+			})
+		}
+	}
+	candidates := []FuelStop{}
+	for _, apt := range apts {
+		best := FuelStop{Apt: apt, OffTrackNM: math.Inf(1)}
+		for _, s := range samples {
+			if d := geo.GlobeDistNM(s.pos, apt.Coord); d < best.OffTrackNM {
+				best.OffTrackNM, best.AlongNM = d, s.along
+			}
+		}
+		if best.OffTrackNM <= fuel_stop_corridor_nm && best.AlongNM > fuel_stop_sample_nm &&
+			total-best.AlongNM > fuel_stop_sample_nm {
+			candidates = append(candidates, best)
+		}
+	}
+
+	// Off track distance is flown at the average fuel per NM of the route
+	perNM := 0.0
+	if total > 0 {
+		perNM = fuelAt(total) / total
+	}
+	climbExtra, descentExtra := n.altitudeChangeExtra()
+
+	// Greedily choose the reachable candidate furthest along the route, only
+	// the final stretch to the destination also requires alternate fuel.
+	// Leaving a stop means flying back to the track and climbing again.
+	usable := f.OnBoard - f.Taxi - f.Reserve
+	stops := []FuelStop{}
+	at, departure := 0.0, 0.0
+	for usable-f.Alternate < fuelAt(total)-fuelAt(at)+departure {
+		var next *FuelStop
+		for i, c := range candidates {
+			burn := fuelAt(c.AlongNM) - fuelAt(at) + departure + c.OffTrackNM*perNM + descentExtra
+			if c.AlongNM > at && burn <= usable && (next == nil || c.AlongNM > next.AlongNM) {
+				next = &candidates[i]
+				next.ArrivalFuel = f.OnBoard - f.Taxi - burn
+			}
+		}
+		if next == nil {
+			return stops, fmt.Errorf("No fuel stop within %d NM of the route is reachable %.0f NM from the origin", fuel_stop_corridor_nm, at)
+		}
+		stops = append(stops, *next)
+		at, departure = next.AlongNM, next.OffTrackNM*perNM+climbExtra
+	}
+	return stops, nil
+}
+
+// Fuel beyond cruising the same distance to climb to the route's altitudes
+// after a stop and to descend from them into one. Descents burning less than
+// cruise aren't credited.
+func (n NavLog) altitudeChangeExtra() (float64, float64) {
+	climb, descent := 0.0, 0.0
+	for _, l := range n.Legs {
+		if l.Leg.Gs <= 0 {
+			continue
+		}
+		perNM := l.Leg.Gph / l.Leg.Gs
+		climb += l.Climb.Fuel - l.Climb.Dist*perNM
+		descent += l.Descent.Fuel - l.Descent.Dist*perNM
+	}
+	return math.Max(climb, 0), math.Max(descent, 0)
+}
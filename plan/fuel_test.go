@@ -0,0 +1,135 @@
+package plan
+
+import (
+	"github.com/cragcraig/flight/aircraft"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"strings"
+	"testing"
+)
+
+// A leg east along the equator, cruising at 100 kts and 10 gph after a climb
+// of climbNM at 30 gph, so each climb costs 0.2 gal per NM more than cruise
+func equatorLeg(from, to float64, climbNM float64) LegLog {
+	dist := (to - from) * 60
+	l := LegLog{
+		From:  Waypoint{Name: "FROM", Pos: geo.NewCoord(0, from)},
+		To:    Waypoint{Name: "TO", Pos: geo.NewCoord(0, to)},
+		Climb: Segment{Dist: climbNM, Fuel: 0.3 * climbNM},
+	}
+	l.Cruise = Segment{Dist: dist - climbNM, Fuel: 0.1 * (dist - climbNM)}
+	l.Leg = Segment{Gs: 100, Gph: 10}
+	l.Leg.add(l.Climb)
+	l.Leg.add(l.Cruise)
+	return l
+}
+
+func equatorLog(legs ...LegLog) NavLog {
+	n := NavLog{Aircraft: aircraft.Profile{Id: "TEST", TaxiGal: 1}}
+	total := Segment{}
+	for _, l := range legs {
+		total.add(l.Leg)
+		l.Total = total
+		n.Legs = append(n.Legs, l)
+	}
+	return n
+}
+
+func checkGal(t *testing.T, name string, got, want float64) {
+	if math.IsNaN(got) || math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s: got %.3f gal, want %.3f gal", name, got, want)
+	}
+}
+
+func TestFuelPlanReserves(t *testing.T) {
+	// 120 NM with a 10 NM climb, then 60 NM
+	n := equatorLog(equatorLeg(0, 2, 10), equatorLeg(2, 3, 0))
+	f := n.FuelPlan(40, ReserveVfrNight, nil)
+	checkGal(t, "Taxi", f.Taxi, 1)
+	checkGal(t, "Trip", f.Trip, 3+11+6)
+	checkGal(t, "Reserve", f.Reserve, 7.5)
+	checkGal(t, "Required", f.Required, 1+20+7.5)
+
+	alternate := equatorLog(equatorLeg(3, 3.5, 0))
+	f = n.FuelPlan(40, ReserveVfrDay, &alternate)
+	checkGal(t, "Alternate", f.Alternate, 3)
+	checkGal(t, "Required with an alternate", f.Required, 1+20+3+5)
+	for _, l := range f.Legs {
+		if len(l.Warnings) != 0 {
+			t.Errorf("Warnings: got %q", l.Warnings)
+		}
+	}
+}
+
+func TestFuelPlanWarnings(t *testing.T) {
+	n := equatorLog(equatorLeg(0, 2, 10), equatorLeg(2, 3, 0))
+	for _, c := range []struct {
+		onBoard  float64
+		warnings [2]string
+	}{
+		{30, [2]string{"", ""}},
+		{25, [2]string{"", "less than the required reserve"}},
+		{20, [2]string{"", "Fuel exhausted"}},
+		{10, [2]string{"Fuel exhausted", "Fuel exhausted"}},
+	} {
+		f := n.FuelPlan(c.onBoard, ReserveVfrDay, nil)
+		checkGal(t, "Remaining", f.Legs[1].Remaining, c.onBoard-21)
+		for i, want := range c.warnings {
+			got := f.Legs[i].Warnings
+			if len(want) == 0 && len(got) != 0 || len(want) != 0 && (len(got) != 1 || !strings.Contains(got[0], want)) {
+				t.Errorf("%.0f gal leg %d warnings: got %q, want %q", c.onBoard, i+1, got, want)
+			}
+		}
+	}
+}
+
+func equatorApt(id string, alongNM, offTrackNM float64) data.Apt {
+	return data.Apt{Id: id, Type: data.Airport, Coord: geo.NewCoord(offTrackNM/60, alongNM/60)}
+}
+
+func TestFuelStops(t *testing.T) {
+	// 300 NM using 34 gal, about 0.113 gal per NM, and 4 gal more to climb
+	// back to altitude after each stop
+	n := equatorLog(equatorLeg(0, 5, 20))
+	apts := []data.Apt{
+		equatorApt("K100", 100, 0),
+		equatorApt("K150", 150, 0),
+		// Further along, but the diversion leaves too little fuel
+		equatorApt("K160", 160, 10),
+		equatorApt("K250", 250, 0),
+	}
+	// 19 gal usable after taxi and reserve
+	f := n.FuelPlan(25, ReserveVfrDay, nil)
+	stops, err := n.FuelStops(f, apts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fuel to climb after K150 leaves too little for the final 150 NM
+	ids := []string{}
+	for _, s := range stops {
+		ids = append(ids, s.Apt.Id)
+	}
+	if strings.Join(ids, " ") != "K150 K250" {
+		t.Fatalf("Stops: got %q, want K150 K250", ids)
+	}
+	checkGal(t, "K150 arrival", stops[0].ArrivalFuel, 24-17)
+	checkGal(t, "K250 arrival", stops[1].ArrivalFuel, 24-34.0/3-4)
+	if math.Abs(stops[0].AlongNM-150) > 0.5 || stops[0].OffTrackNM > 0.5 {
+		t.Errorf("K150: got %.1f NM along, %.1f NM off track", stops[0].AlongNM, stops[0].OffTrackNM)
+	}
+
+	// Without K250 there is no way on from K160
+	if _, err := n.FuelStops(f, apts[:3]); err == nil {
+		t.Error("Expected an error without a reachable second stop")
+	}
+}
+
+func TestFuelStopsEmptyRoute(t *testing.T) {
+	n := equatorLog(equatorLeg(0, 0, 0))
+	f := n.FuelPlan(0, ReserveVfrDay, nil)
+	stops, err := n.FuelStops(f, []data.Apt{equatorApt("K0", 0, 1)})
+	if err == nil || len(stops) != 0 {
+		t.Errorf("Got %v, %v, want no stops and an error", stops, err)
+	}
+}
@@ -0,0 +1,258 @@
+package plan
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/aircraft"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"time"
+)
+
+// Altitude changes are considered complete within this distance of the fix
+const transition_tolerance_nm = 0.1
+
+// Portion of a leg flown in a single phase of flight
+type Segment struct {
+	Heading float64 // True
+	Tas, Gs float64 // Knots
+	Gph     float64
+	Dist    float64 // NM
+	Ete     time.Duration
+	Fuel    float64 // Gallons
+}
+
+func (s *Segment) add(o Segment) {
+	s.Dist += o.Dist
+	s.Ete += o.Ete
+	s.Fuel += o.Fuel
+}
+
+// Navigation log entry for the leg between two consecutive waypoints
+type LegLog struct {
+	From, To       Waypoint
+	Alt            int      // Cruise altitude
+	Course         float64  // True course, compass degrees
+	Wca            float64  // Wind correction angle in cruise, degrees right of course
+	Heading        float64  // True heading in cruise
+	MagHeading     *float64 // Unknown without magnetic variation
+	CompassHeading *float64 // Unknown without a deviation card
+	Climb          Segment  // Climb from the departure waypoint, if the leg climbs
+	Cruise         Segment
+	Descent        Segment   // Descent to the arrival waypoint, if the leg descends
+	Toc            *Waypoint // Top of climb, if reached before the arrival waypoint
+	Tod            *Waypoint // Top of descent, if after the departure waypoint
+	Leg            Segment   // Totals for the whole leg, Tas, Gs and Gph are for cruise
+	Total          Segment   // Cumulative totals from the origin through this leg
+	Warnings       []string
+}
+
+type NavLog struct {
+	Aircraft aircraft.Profile
+	Legs     []LegLog
+}
+
+func (n NavLog) Total() Segment {
+	if len(n.Legs) == 0 {
+		return Segment{}
+	}
+	return n.Legs[len(n.Legs)-1].Total
+}
+
+// Computes the navigation log for flying the plan in the given aircraft.
+// Each leg is flown at the power setting of its departure waypoint unless rpm
+// is non-zero, with the profile's default used for waypoints that have none.
+// Winds and temperature aloft come from the departure waypoint's weather.
+func (p Plan) NavLog(ac aircraft.Profile, rpm int) (NavLog, error) {
+	if err := p.Validate(); err != nil {
+		return NavLog{}, err
+	}
+	n := NavLog{Aircraft: ac}
+	total := Segment{}
+	for i := 0; i < p.Legs(); i++ {
+		l, err := p.legLog(i, ac, rpm)
+		if err != nil {
+			return NavLog{}, err
+		}
+		total.add(l.Leg)
+		l.Total = total
+		n.Legs = append(n.Legs, l)
+	}
+	return n, nil
+}
+
+func (p Plan) legLog(i int, ac aircraft.Profile, rpm int) (LegLog, error) {
+	from, to := p.Waypoints[i], p.Waypoints[i+1]
+	l := LegLog{
+		From: from,
+		To:   to,
+		Alt:  p.LegAlt(i),
+	}
+	g, err := geo.Inverse(from.Pos, to.Pos)
+	if err != nil {
+		return LegLog{}, fmt.Errorf("Leg %s to %s: %s", from.Name, to.Name, err)
+	}
+	course, dist := g.InitialAzimuth, g.DistNM
+	l.Course = course
+	wind := geo.Vect{X: 0, Y: 0}
+	if from.Wx.Wind != nil {
+		wind = *from.Wx.Wind
+	}
+	isaDev := 0.0
+	if from.Wx.Temp != nil {
+		isaDev = *from.Wx.Temp - aircraft.IsaTemp(l.Alt)
+		// Performance at the edge of the tables beats none at all
+		if limited, clamped := ac.ClampIsaDev(isaDev); clamped {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("ISA%+.0f is outside the %s performance tables, using ISA%+.0f", isaDev, ac.Id, limited))
+			isaDev = limited
+		}
+	}
+	if rpm == 0 {
+		rpm = from.Rpm
+	}
+	if rpm == 0 {
+		rpm = ac.DefaultRpm
+	}
+
+	// Altitude changes happen at the start of a climbing leg and at the end of a descending one
+	if to.Alt > from.Alt {
+		perf, err := ac.Climb((from.Alt+to.Alt)/2, isaDev)
+		if err != nil {
+			return LegLog{}, err
+		}
+		if l.Climb, err = altitudeChange(course, wind, perf, from.Fpm, to.Alt-from.Alt); err != nil {
+			return LegLog{}, fmt.Errorf("Leg %s to %s: %s", from.Name, to.Name, err)
+		} else if l.Climb.Dist > dist+transition_tolerance_nm {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("Unable to climb to %dft before %s", to.Alt, to.Name))
+		} else if l.Climb.Dist < dist-transition_tolerance_nm {
+			l.Toc = transition(Toc, from, to, l.Climb.Dist/dist, from.Name, l.Climb.Dist)
+			l.Toc.Alt, l.Toc.Fpm, l.Toc.Rpm = to.Alt, 0, from.Rpm
+			if from.Wx.Eta != nil {
+				eta := from.Wx.Eta.Add(l.Climb.Ete)
+				l.Toc.Wx.Eta = &eta
+			}
+		}
+	} else if to.Alt < from.Alt {
+		perf, err := ac.Descent((from.Alt+to.Alt)/2, isaDev)
+		if err != nil {
+			return LegLog{}, err
+		}
+		if l.Descent, err = altitudeChange(course, wind, perf, from.Fpm, from.Alt-to.Alt); err != nil {
+			return LegLog{}, fmt.Errorf("Leg %s to %s: %s", from.Name, to.Name, err)
+		} else if l.Descent.Dist > dist+transition_tolerance_nm {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("Unable to descend to %dft before %s", to.Alt, to.Name))
+		} else if l.Descent.Dist < dist-transition_tolerance_nm {
+			l.Tod = transition(Tod, from, to, 1-l.Descent.Dist/dist, to.Name, l.Descent.Dist)
+			l.Tod.Alt, l.Tod.Fpm, l.Tod.Rpm = from.Alt, from.Fpm, from.Rpm
+		}
+	}
+
+	perf, err := ac.Cruise(l.Alt, isaDev, rpm)
+	if err != nil {
+		return LegLog{}, err
+	}
+	h, gs, err := geo.WindTriangle(geo.Compass2Rad(course), perf.Tas, wind)
+	if err != nil {
+		return LegLog{}, fmt.Errorf("Leg %s to %s: %s", from.Name, to.Name, err)
+	}
+	l.Heading = geo.Rad2Compass(h)
+	l.Wca = geo.Rad2Deg(geo.Compass2Rad(course) - h)
+	if from.Wx.Variation != nil {
+		mh := geo.Wrap360(l.Heading + float64(*from.Wx.Variation))
+		l.MagHeading = &mh
+		if len(ac.DeviationCard) != 0 {
+			ch := ac.DeviationCard.Compass(mh)
+			l.CompassHeading = &ch
+		}
+	}
+	// Altitude changes that can't be completed are flown for the whole leg
+	l.Climb = l.Climb.limit(dist)
+	l.Descent = l.Descent.limit(dist)
+	cruiseDist := math.Max(dist-l.Climb.Dist-l.Descent.Dist, 0)
+	l.Cruise = flown(h, perf.Tas, gs, perf.Gph, cruiseDist)
+	if l.Tod != nil && from.Wx.Eta != nil {
+		eta := from.Wx.Eta.Add(l.Climb.Ete + l.Cruise.Ete)
+		l.Tod.Wx.Eta = &eta
+	}
+
+	l.Leg = Segment{Heading: l.Heading, Tas: perf.Tas, Gs: gs, Gph: perf.Gph}
+	l.Leg.add(l.Climb)
+	l.Leg.add(l.Cruise)
+	l.Leg.add(l.Descent)
+	return l, nil
+}
+
+// Climb or descent of dFt at fpm, or the profile rate if zero
+func altitudeChange(course float64, wind geo.Vect, perf aircraft.Performance, fpm, dFt int) (Segment, error) {
+	rate := math.Abs(float64(fpm))
+	if rate == 0 {
+		rate = perf.Fpm
+	}
+	if rate == 0 {
+		return Segment{}, fmt.Errorf("No climb or descent rate for a %dft altitude change", dFt)
+	}
+	h, gs, err := geo.WindTriangle(geo.Compass2Rad(course), perf.Tas, wind)
+	if err != nil {
+		return Segment{}, err
+	}
+	hours := float64(dFt) / rate / 60
+	return flown(h, perf.Tas, gs, perf.Gph, gs*hours), nil
+}
+
+// Segment flown at the given true heading (real angle) and speeds
+func flown(heading, tas, gs, gph, dist float64) Segment {
+	hours := dist / gs
+	return Segment{
+		Heading: geo.Rad2Compass(heading),
+		Tas:     tas,
+		Gs:      gs,
+		Gph:     gph,
+		Dist:    dist,
+		Ete:     time.Duration(hours * float64(time.Hour)),
+		Fuel:    hours * gph,
+	}
+}
+
+// The part of the segment flown within dist
+func (s Segment) limit(dist float64) Segment {
+	if s.Dist <= dist {
+		return s
+	}
+	hours := dist / s.Gs
+	s.Dist = dist
+	s.Ete = time.Duration(hours * float64(time.Hour))
+	s.Fuel = hours * s.Gph
+	return s
+}
+
+// Synthetic waypoint the given fraction of the way along the leg, named for
+// its distance from fix, e.g., KBDU/12.3
+func transition(kind string, from, to Waypoint, fraction float64, fix string, dist float64) *Waypoint {
+	return &Waypoint{
+		Kind: kind,
+		Name: fmt.Sprintf("%s/%.1f", fix, dist),
+		Pos:  geo.IntermediatePoint(from.Pos, to.Pos, fraction),
+		Wx: Weather{
+			Wind:      from.Wx.Wind,
+			Temp:      from.Wx.Temp,
+			Variation: from.Wx.Variation,
+		},
+	}
+}
+
+// The plan with top of climb and descent waypoints inserted
+func (n NavLog) Plan() Plan {
+	p := Plan{}
+	for _, l := range n.Legs {
+		p.Waypoints = append(p.Waypoints, l.From)
+		if l.Toc != nil {
+			p.Waypoints = append(p.Waypoints, *l.Toc)
+		}
+		if l.Tod != nil {
+			p.Waypoints = append(p.Waypoints, *l.Tod)
+		}
+	}
+	if len(n.Legs) != 0 {
+		p.Waypoints = append(p.Waypoints, n.Legs[len(n.Legs)-1].To)
+	}
+	return p
+}
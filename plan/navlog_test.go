@@ -0,0 +1,158 @@
+package plan
+
+import (
+	"github.com/cragcraig/flight/aircraft"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"strings"
+	"testing"
+)
+
+func readPlan(t *testing.T, text string) Plan {
+	p, err := Read(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// The built-in profile, not any of the user's own
+func loadAircraft(t *testing.T, id string) aircraft.Profile {
+	t.Setenv("HOME", t.TempDir())
+	ac, err := aircraft.Load(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ac
+}
+
+func checkNear(t *testing.T, name string, got, want, tolerance float64) {
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s: got %.4f, want %.4f", name, got, want)
+	}
+}
+
+// ISA at 9000ft is -2.8C, so 22C aloft is about ISA+25
+const hotDayPlan = `FLGT 3
+ORIGIN KBDU 40.039389,-105.225806 9000 0 2400
+  TEMP 22
+DEST KCOS 38.805805,-104.700778 9000 0 2400
+`
+
+func TestNavLogOutsideIsaTables(t *testing.T) {
+	ac := loadAircraft(t, "C172")
+	n, err := readPlan(t, hotDayPlan).NavLog(ac, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := n.Legs[0]
+	if len(l.Warnings) != 1 || !strings.Contains(l.Warnings[0], "ISA+25") || !strings.Contains(l.Warnings[0], "ISA+20") {
+		t.Errorf("Warnings: got %q", l.Warnings)
+	}
+	// Flown with the performance at the edge of the tables
+	perf, err := ac.Cruise(9000, 20, 2400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Leg.Tas != perf.Tas || l.Leg.Gph != perf.Gph {
+		t.Errorf("Cruise: got %.1f kts %.2f gph, want %.1f kts %.2f gph", l.Leg.Tas, l.Leg.Gph, perf.Tas, perf.Gph)
+	}
+}
+
+func kinds(p Plan) string {
+	k := []string{}
+	for _, w := range p.Waypoints {
+		k = append(k, w.Kind)
+	}
+	return strings.Join(k, " ")
+}
+
+// Climbing 60 NM north to 9500ft, then descending 60 NM further north
+const climbDescendPlan = `FLGT 3
+ORIGIN KBDU 40.039389,-105.225806 5288 700 2500
+WPT NORTH 41.039389,-105.225806 9500 500 2500
+DEST SOUTH 42.039389,-105.225806 5300 0 0
+`
+
+func TestNavLogTopOfClimbAndDescent(t *testing.T) {
+	ac := loadAircraft(t, "C172")
+	n, err := readPlan(t, climbDescendPlan).NavLog(ac, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	climb, descent := n.Legs[0], n.Legs[1]
+	if climb.Toc == nil || climb.Tod != nil || descent.Toc != nil || descent.Tod == nil {
+		t.Fatalf("Got TOC %v, TOD %v then TOC %v, TOD %v, want a TOC then a TOD", climb.Toc, climb.Tod, descent.Toc, descent.Tod)
+	}
+	// In still air, the TAS for the time to climb at 700 fpm and descend at 500 fpm
+	climbPerf, err := ac.Climb((5288+9500)/2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	descentPerf, err := ac.Descent((9500+5300)/2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNear(t, "Climb", climb.Climb.Dist, climbPerf.Tas*(9500-5288)/700/60, 1e-6)
+	checkNear(t, "Descent", descent.Descent.Dist, descentPerf.Tas*(9500-5300)/500/60, 1e-6)
+	toc, tod := *climb.Toc, *descent.Tod
+	checkNear(t, "TOC distance from KBDU", geo.DistanceNM(climb.From.Pos, toc.Pos), climb.Climb.Dist, 0.05)
+	checkNear(t, "TOD distance to SOUTH", geo.DistanceNM(tod.Pos, descent.To.Pos), descent.Descent.Dist, 0.05)
+	if toc.Alt != 9500 || tod.Alt != 9500 || !strings.HasPrefix(toc.Name, "KBDU/") || !strings.HasPrefix(tod.Name, "SOUTH/") {
+		t.Errorf("Got TOC %s at %dft, TOD %s at %dft", toc.Name, toc.Alt, tod.Name, tod.Alt)
+	}
+	checkNear(t, "Climb leg cruise", climb.Cruise.Dist, climb.Leg.Dist-climb.Climb.Dist, 1e-9)
+	if got := kinds(n.Plan()); got != "ORIGIN TOC WPT TOD DEST" {
+		t.Errorf("Plan: got %s", got)
+	}
+}
+
+// A mile and a half to 9000ft
+const shortClimbPlan = `FLGT 3
+ORIGIN KBDU 40.039389,-105.225806 5288 700 2500
+DEST NORTH 40.064389,-105.225806 9000 0 0
+`
+
+func TestNavLogClimbNotCompleted(t *testing.T) {
+	n, err := readPlan(t, shortClimbPlan).NavLog(loadAircraft(t, "C172"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := n.Legs[0]
+	if l.Toc != nil || len(l.Warnings) != 1 || !strings.Contains(l.Warnings[0], "Unable to climb") {
+		t.Errorf("Got TOC %v, warnings %q, want no TOC and a warning", l.Toc, l.Warnings)
+	}
+	// Climbing for the whole leg
+	checkNear(t, "Climb", l.Climb.Dist, l.Leg.Dist, 1e-9)
+	checkNear(t, "Cruise", l.Cruise.Dist, 0, 1e-9)
+	checkNear(t, "Fuel", l.Leg.Fuel, l.Climb.Fuel, 1e-9)
+	if got := kinds(n.Plan()); got != "ORIGIN DEST" {
+		t.Errorf("Plan: got %s", got)
+	}
+}
+
+// Neither the climb to 9500ft nor the descent from it fit in 3 NM legs, so
+// the climb is still under way where the descent must already have started
+const overlappingPlan = `FLGT 3
+ORIGIN KBDU 40.039389,-105.225806 5288 700 2500
+WPT NORTH 40.089389,-105.225806 9500 500 2500
+DEST SOUTH 40.139389,-105.225806 5300 0 0
+`
+
+func TestNavLogClimbAndDescentOverlap(t *testing.T) {
+	n, err := readPlan(t, overlappingPlan).NavLog(loadAircraft(t, "C172"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"Unable to climb", "Unable to descend"} {
+		l := n.Legs[i]
+		if l.Toc != nil || l.Tod != nil || len(l.Warnings) != 1 || !strings.Contains(l.Warnings[0], want) {
+			t.Errorf("Leg %d: got TOC %v, TOD %v, warnings %q, want %q", i+1, l.Toc, l.Tod, l.Warnings, want)
+		}
+		checkNear(t, "Cruise", l.Cruise.Dist, 0, 1e-9)
+		checkNear(t, "Altitude change", l.Climb.Dist+l.Descent.Dist, l.Leg.Dist, 1e-9)
+	}
+	if got := kinds(n.Plan()); got != "ORIGIN WPT DEST" {
+		t.Errorf("Plan: got %s", got)
+	}
+}
@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/geo"
+	"time"
+)
+
+// Waypoint kinds
+const (
+	Origin  = "ORIGIN"
+	Enroute = "WPT"
+	Dest    = "DEST"
+	Toc     = "TOC" // Top of climb, computed by NavLog
+	Tod     = "TOD" // Top of descent, computed by NavLog
+)
+
+type Waypoint struct {
+	Kind string
+	Name string // Airport identifier or position as entered, e.g., KBDU+5N
+	Pos  geo.Coord
+	Alt  int // Planned altitude over the waypoint, ft MSL
+	Fpm  int // Climb or descent rate leaving the waypoint
+	Rpm  int // Power setting leaving the waypoint
+	Wx   Weather
+}
+
+// Conditions expected over a waypoint, any of which may be unknown
+type Weather struct {
+	Eta       *time.Time
+	Wind      *geo.Vect // Aloft along the leg, see parse.ParseGeoVect
+	Temp      *float64  // Aloft along the leg, Celsius
+	Variation *int      // Magnetic variation, west positive as in data.Apt
+	Metar     string    // Raw surface observation
+	Taf       string    // Raw surface forecast
+}
+
+func (w Waypoint) String() string {
+	return fmt.Sprintf("{%s %dft %dfpm %drpm}", w.Name, w.Alt, w.Fpm, w.Rpm)
+}
+
+// A flight from an origin to a destination through zero or more waypoints
+type Plan struct {
+	Waypoints []Waypoint
+}
+
+func (p Plan) Origin() Waypoint {
+	return p.Waypoints[0]
+}
+
+func (p Plan) Dest() Waypoint {
+	return p.Waypoints[len(p.Waypoints)-1]
+}
+
+// Number of legs between consecutive waypoints
+func (p Plan) Legs() int {
+	return len(p.Waypoints) - 1
+}
+
+func (p Plan) Validate() error {
+	if len(p.Waypoints) < 2 {
+		return errors.New("Flight plan requires an origin and a destination")
+	}
+	for i, w := range p.Waypoints {
+		expected := Enroute
+		if i == 0 {
+			expected = Origin
+		} else if i == len(p.Waypoints)-1 {
+			expected = Dest
+		}
+		if expected == Enroute && (w.Kind == Toc || w.Kind == Tod) {
+			continue
+		} else if w.Kind != expected {
+			return fmt.Errorf("Flight plan waypoint #%d (%s) is %s, expected %s", i+1, w.Name, w.Kind, expected)
+		}
+	}
+	return nil
+}
+
+// Cruise altitude of the leg departing waypoint i
+func (p Plan) LegAlt(i int) int {
+	a, b := p.Waypoints[i].Alt, p.Waypoints[i+1].Alt
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,92 @@
+package taf
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/aviationweather"
+	"github.com/cragcraig/flight/geo"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const aviationWeatherUrl = "https://aviationweather.gov/api/data/taf"
+
+// Provider backed by the aviationweather.gov data API
+// See https://aviationweather.gov/data/api/
+type AviationWeather struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+func NewAviationWeather() AviationWeather {
+	return AviationWeather{
+		BaseUrl: aviationWeatherUrl,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (a AviationWeather) QueryStations(stations []string, hoursBeforeNow float64) ([]Taf, error) {
+	parameters := url.Values{}
+	parameters.Add("ids", strings.Join(stations, ","))
+	return a.query(parameters, hoursBeforeNow)
+}
+
+func (a AviationWeather) QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Taf, error) {
+	// The API only supports rectangular queries, so filter the corners afterwards
+	nm := geo.StatuteMiles2NM(float64(radius))
+	sw, ne := geo.BoundingBox(coord, nm)
+	parameters := url.Values{}
+	parameters.Add("bbox", fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", sw.Lat(), sw.Lon(), ne.Lat(), ne.Lon()))
+	tafs, err := a.query(parameters, hoursBeforeNow)
+	if err != nil {
+		return []Taf{}, err
+	}
+	within := []Taf{}
+	for _, t := range tafs {
+		if geo.GlobeDistNM(coord, t.Coord()) <= nm {
+			within = append(within, t)
+		}
+	}
+	return within, nil
+}
+
+func (a AviationWeather) buildQueryUrl(parameters url.Values, hoursBeforeNow float64) string {
+	u, err := url.Parse(a.BaseUrl)
+	if err != nil {
+		panic("bad base url: " + a.BaseUrl)
+	}
+	parameters.Add("format", "xml")
+	parameters.Add("time", "issue")
+	parameters.Add("hours", fmt.Sprintf("%.2f", hoursBeforeNow))
+	u.RawQuery = parameters.Encode()
+	return u.String()
+}
+
+func (a AviationWeather) query(parameters url.Values, hoursBeforeNow float64) ([]Taf, error) {
+	queryUrl := a.buildQueryUrl(parameters, hoursBeforeNow)
+	body, err := aviationweather.Fetch(a.Client, queryUrl, "TAF")
+	if err != nil {
+		return []Taf{}, err
+	}
+	if len(body) == 0 {
+		// No matching forecasts
+		return []Taf{}, nil
+	}
+	tafs, err := unmarshalXml(body)
+	if err != nil {
+		return []Taf{}, err
+	}
+	return mostRecentForEachStation(tafs), nil
+}
+
+// Keeps only the latest forecast for each station, preserving response order
+func mostRecentForEachStation(tafs []Taf) []Taf {
+	result := []Taf{}
+	for _, i := range aviationweather.MostRecent(len(tafs), func(i int) (string, string) {
+		return tafs[i].StationId, tafs[i].IssueTime
+	}) {
+		result = append(result, tafs[i])
+	}
+	return result
+}
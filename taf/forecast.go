@@ -0,0 +1,259 @@
+package taf
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/metar"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change group type of a forecast period
+const (
+	Initial = "INITIAL" // Conditions from the start of the valid period
+	From    = "FM"      // Rapid change, replaces all prevailing conditions
+	Becmg   = "BECMG"   // Gradual change of the stated elements
+	Tempo   = "TEMPO"   // Temporary fluctuations
+	Prob    = "PROB"    // Probability of occurrence, possibly combined with TEMPO
+)
+
+type Period struct {
+	Type        string
+	Probability int // Percent, zero unless a PROB group
+	Start, End  time.Time
+	Wind        *metar.Wind
+	Visibility  *metar.Visibility
+	Weather     []metar.Weather
+	Sky         []metar.SkyLayer
+	WindShear   string
+	Raw         string
+}
+
+// Prevailing (FM, BECMG) rather than transient (TEMPO, PROB) conditions
+func (p Period) IsPrevailing() bool {
+	return p.Type == Initial || p.Type == From || p.Type == Becmg
+}
+
+func (p Period) String() string {
+	span := fmt.Sprintf("%s - %s", p.Start.Format("02 1504Z"), p.End.Format("02 1504Z"))
+	kind := p.Type
+	if p.Type == Prob {
+		kind = fmt.Sprintf("PROB%d", p.Probability)
+	} else if p.Probability != 0 {
+		kind = fmt.Sprintf("PROB%d %s", p.Probability, p.Type)
+	}
+	return fmt.Sprintf("%-13s %s  %s", kind, span, p.Raw)
+}
+
+type Forecast struct {
+	Station            string
+	Issued             time.Time
+	ValidFrom          time.Time
+	ValidTo            time.Time
+	Amended, Corrected bool
+	Periods            []Period
+}
+
+// Forecast conditions in effect at a moment
+type Conditions struct {
+	Wind           *metar.Wind
+	Visibility     *metar.Visibility
+	Weather        []metar.Weather
+	Sky            []metar.SkyLayer
+	CeilingFt      int
+	HasCeiling     bool
+	FlightCategory string
+	// TEMPO and PROB groups in effect, which may be worse than prevailing
+	Temporary []Period
+}
+
+var (
+	issuedRegexp   = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	spanRegexp     = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	fromRegexp     = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	probRegexp     = regexp.MustCompile(`^PROB(\d{2})$`)
+	windShearRegex = regexp.MustCompile(`^WS\d{3}/\d{5,6}KT$`)
+)
+
+// Decodes a raw TAF into forecast periods. Since a TAF only encodes the day of
+// the month, ref must be a time near the issue time, e.g., the issue time
+// reported by the web service.
+// e.g., TAF KBJC 181720Z 1818/1918 27012G20KT P6SM SCT080 FM182300 30008KT P6SM BKN100 TEMPO 1900/1904 3SM -SHRA BKN050
+func Parse(raw string, ref time.Time) (Forecast, error) {
+	tokens := strings.Fields(strings.ToUpper(raw))
+	f := Forecast{}
+	for len(tokens) > 0 && (tokens[0] == "TAF" || tokens[0] == "AMD" || tokens[0] == "COR") {
+		f.Amended = f.Amended || tokens[0] == "AMD"
+		f.Corrected = f.Corrected || tokens[0] == "COR"
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 3 {
+		return Forecast{}, errors.New("TAF is too short: " + raw)
+	}
+	f.Station = tokens[0]
+	if m := issuedRegexp.FindStringSubmatch(tokens[1]); m == nil {
+		return Forecast{}, errors.New("TAF has an invalid issue time: " + tokens[1])
+	} else {
+		f.Issued = resolveTime(ref, atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}
+	if from, to, ok := parseSpan(f.Issued, tokens[2]); !ok {
+		return Forecast{}, errors.New("TAF has an invalid valid period: " + tokens[2])
+	} else {
+		f.ValidFrom, f.ValidTo = from, to
+	}
+
+	// Split into change groups
+	current := Period{Type: Initial, Start: f.ValidFrom}
+	groupTokens := []string{}
+	finish := func() {
+		current.Raw = strings.Join(groupTokens, " ")
+		f.Periods = append(f.Periods, current)
+		groupTokens = []string{}
+	}
+	for i := 3; i < len(tokens); i++ {
+		t := tokens[i]
+		if m := fromRegexp.FindStringSubmatch(t); m != nil {
+			finish()
+			current = Period{
+				Type:  From,
+				Start: resolveTime(f.Issued, atoi(m[1]), atoi(m[2]), atoi(m[3])),
+			}
+		} else if t == Becmg || t == Tempo {
+			if current.Type == Prob && len(groupTokens) == 1 {
+				// PROB30 TEMPO 1912/1916
+				current.Type = t
+			} else {
+				finish()
+				current = Period{Type: t}
+			}
+		} else if m := probRegexp.FindStringSubmatch(t); m != nil {
+			finish()
+			current = Period{Type: Prob, Probability: atoi(m[1])}
+		} else if from, to, ok := parseSpan(f.Issued, t); ok && current.Start.IsZero() {
+			current.Start, current.End = from, to
+		} else if w, ok := metar.ParseWind(t); ok {
+			current.Wind = &w
+		} else if v, n, ok := metar.ParseVisibility(tokens[i:]); ok {
+			current.Visibility = &v
+			groupTokens = append(groupTokens, tokens[i:i+n-1]...)
+			i += n - 1
+			t = tokens[i]
+		} else if s, ok := metar.ParseSkyLayer(t); ok {
+			current.Sky = append(current.Sky, s)
+		} else if w, ok := metar.ParseWeather(t); ok {
+			current.Weather = append(current.Weather, w)
+		} else if t == "NSW" {
+			current.Weather = []metar.Weather{}
+		} else if windShearRegex.MatchString(t) {
+			current.WindShear = t
+		}
+		groupTokens = append(groupTokens, t)
+	}
+	finish()
+
+	// Prevailing groups run until the next FM group or the end of the forecast
+	for i := range f.Periods {
+		p := &f.Periods[i]
+		if !p.End.IsZero() {
+			continue
+		}
+		p.End = f.ValidTo
+		for _, next := range f.Periods[i+1:] {
+			if next.Type == From {
+				p.End = next.Start
+				break
+			}
+		}
+	}
+	return f, nil
+}
+
+// Forecast conditions in effect at time t
+func (f Forecast) ConditionsAt(t time.Time) (Conditions, error) {
+	if t.Before(f.ValidFrom) || !t.Before(f.ValidTo) {
+		return Conditions{}, fmt.Errorf("%s is outside of the %s TAF valid period", t.UTC().Format(time.RFC3339), f.Station)
+	}
+	c := Conditions{}
+	for _, p := range f.Periods {
+		if p.Type == Initial || p.Type == From {
+			if p.Start.After(t) {
+				continue
+			}
+			// Replaces all prevailing conditions
+			c = Conditions{}
+			applyPeriod(&c, p)
+		} else if p.Type == Becmg {
+			// The change is expected to be complete by the end of the group
+			if !p.End.After(t) {
+				applyPeriod(&c, p)
+			}
+		} else if !p.Start.After(t) && p.End.After(t) {
+			c.Temporary = append(c.Temporary, p)
+		}
+	}
+	c.CeilingFt, c.HasCeiling = metar.Ceiling(c.Sky)
+	if c.Visibility != nil {
+		c.FlightCategory = metar.FlightCategory(c.CeilingFt, c.HasCeiling, c.Visibility.SM)
+	}
+	return c, nil
+}
+
+// Overlays the elements forecast in p
+func applyPeriod(c *Conditions, p Period) {
+	if p.Wind != nil {
+		c.Wind = p.Wind
+	}
+	if p.Visibility != nil {
+		c.Visibility = p.Visibility
+	}
+	if p.Weather != nil {
+		c.Weather = p.Weather
+	}
+	if p.Sky != nil {
+		c.Sky = p.Sky
+	}
+}
+
+// e.g., 1818/1918
+func parseSpan(ref time.Time, s string) (time.Time, time.Time, bool) {
+	m := spanRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	from := resolveTime(ref, atoi(m[1]), atoi(m[2]), 0)
+	to := resolveTime(from, atoi(m[3]), atoi(m[4]), 0)
+	return from, to, true
+}
+
+// Time with the given day of the month that is nearest to ref.
+// Hour 24 is the end of the day.
+func resolveTime(ref time.Time, day, hour, minute int) time.Time {
+	ref = ref.UTC()
+	best := time.Time{}
+	for _, monthOffset := range []int{-1, 0, 1} {
+		first := time.Date(ref.Year(), ref.Month()+time.Month(monthOffset), 1, 0, 0, 0, 0, time.UTC)
+		candidate := first.AddDate(0, 0, day-1).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		if candidate.Day() != day && hour != 24 {
+			// Day does not exist in this month
+			continue
+		}
+		if best.IsZero() || absDuration(candidate.Sub(ref)) < absDuration(best.Sub(ref)) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
@@ -0,0 +1,170 @@
+package taf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const kbjcTaf = "TAF AMD KBJC 181720Z 1818/1918 27012G20KT P6SM SCT080 " +
+	"FM182300 30008KT P6SM BKN100 " +
+	"BECMG 1902/1904 BKN030 " +
+	"TEMPO 1900/1904 3SM -SHRA BKN050 " +
+	"PROB30 TEMPO 1906/1910 1SM TSRA OVC008CB " +
+	"FM191200 VRB03KT 2SM BR OVC004"
+
+var kbjcIssued = time.Date(2026, 10, 18, 17, 20, 0, 0, time.UTC)
+
+func at(day, hour int) time.Time {
+	return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC)
+}
+
+func parseKbjc(t *testing.T) Forecast {
+	f, err := Parse(kbjcTaf, kbjcIssued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestParse(t *testing.T) {
+	f := parseKbjc(t)
+	if f.Station != "KBJC" || !f.Amended || f.Corrected || !f.Issued.Equal(kbjcIssued) {
+		t.Errorf("Header: got %s, amended %t, corrected %t, issued %s", f.Station, f.Amended, f.Corrected, f.Issued)
+	}
+	if !f.ValidFrom.Equal(at(18, 18)) || !f.ValidTo.Equal(at(19, 18)) {
+		t.Errorf("Valid: got %s - %s", f.ValidFrom, f.ValidTo)
+	}
+	for i, want := range []struct {
+		kind        string
+		probability int
+		start, end  time.Time
+		raw         string
+	}{
+		{Initial, 0, at(18, 18), at(18, 23), "27012G20KT P6SM SCT080"},
+		{From, 0, at(18, 23), at(19, 12), "FM182300 30008KT P6SM BKN100"},
+		{Becmg, 0, at(19, 2), at(19, 4), "BECMG 1902/1904 BKN030"},
+		{Tempo, 0, at(19, 0), at(19, 4), "TEMPO 1900/1904 3SM -SHRA BKN050"},
+		{Tempo, 30, at(19, 6), at(19, 10), "PROB30 TEMPO 1906/1910 1SM TSRA OVC008CB"},
+		{From, 0, at(19, 12), at(19, 18), "FM191200 VRB03KT 2SM BR OVC004"},
+	} {
+		if i >= len(f.Periods) {
+			t.Fatalf("Got %d periods, want 6", len(f.Periods))
+		}
+		p := f.Periods[i]
+		if p.Type != want.kind || p.Probability != want.probability || !p.Start.Equal(want.start) || !p.End.Equal(want.end) || p.Raw != want.raw {
+			t.Errorf("Period %d: got %s", i, p)
+		}
+	}
+	if len(f.Periods) != 6 {
+		t.Errorf("Got %d periods, want 6", len(f.Periods))
+	}
+	initial := f.Periods[0]
+	if initial.Wind == nil || initial.Wind.Dir != 270 || initial.Wind.Speed != 12 || initial.Wind.Gust != 20 {
+		t.Errorf("Initial wind: got %+v", initial.Wind)
+	}
+	if p := f.Periods[4]; len(p.Weather) != 1 || p.Weather[0].Descriptor != "TS" || len(p.Sky) != 1 || p.Sky[0].Cloud != "CB" {
+		t.Errorf("PROB30 TEMPO: got weather %+v, sky %+v", p.Weather, p.Sky)
+	}
+}
+
+func TestParseProb(t *testing.T) {
+	f, err := Parse("KBJC 181720Z 1818/1918 27012KT P6SM SCT080 PROB40 1820/1822 2SM TSRA BKN020CB", kbjcIssued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Periods) != 2 {
+		t.Fatalf("Got %d periods, want 2", len(f.Periods))
+	}
+	p := f.Periods[1]
+	if p.Type != Prob || p.Probability != 40 || p.IsPrevailing() || !p.Start.Equal(at(18, 20)) || !p.End.Equal(at(18, 22)) {
+		t.Errorf("PROB40: got %s", p)
+	}
+	c, err := f.ConditionsAt(at(18, 21))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.FlightCategory != "VFR" || len(c.Temporary) != 1 || c.Temporary[0].Probability != 40 {
+		t.Errorf("Got %s with %v, want VFR with the PROB40 group", c.FlightCategory, c.Temporary)
+	}
+}
+
+// Across the end of the month, with a period ending at 24Z
+func TestParseMonthEnd(t *testing.T) {
+	f, err := Parse("KBJC 312330Z 0100/0124 27012KT P6SM SKC", time.Date(2026, 10, 31, 23, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.ValidFrom.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)) || !f.ValidTo.Equal(time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Valid: got %s - %s", f.ValidFrom, f.ValidTo)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, raw := range []string{
+		"TAF KBJC",
+		"TAF KBJC 1817Z 1818/1918 27012KT",
+		"TAF KBJC 181720Z 18/19 27012KT",
+	} {
+		if _, err := Parse(raw, kbjcIssued); err == nil {
+			t.Errorf("Expected an error for %q", raw)
+		}
+	}
+}
+
+func TestConditionsAt(t *testing.T) {
+	f := parseKbjc(t)
+	for _, want := range []struct {
+		at        time.Time
+		wind      string
+		ceilingFt int // Zero for no ceiling
+		category  string
+		temporary string
+	}{
+		{at(18, 20), "270@12G20", 0, "VFR", ""},
+		// TEMPO in effect, BECMG still under way
+		{at(19, 1), "300@8", 10000, "VFR", "TEMPO 1900/1904 3SM -SHRA BKN050"},
+		// BECMG complete, only changing the sky
+		{at(19, 5), "300@8", 3000, "MVFR", ""},
+		{at(19, 7), "300@8", 3000, "MVFR", "PROB30 TEMPO 1906/1910 1SM TSRA OVC008CB"},
+		// FM replaces everything
+		{at(19, 13), "VRB@3", 400, "LIFR", ""},
+	} {
+		c, err := f.ConditionsAt(want.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wind := ""
+		if c.Wind != nil {
+			wind = fmt.Sprintf("%d@%d", c.Wind.Dir, c.Wind.Speed)
+			if c.Wind.Variable {
+				wind = fmt.Sprintf("VRB@%d", c.Wind.Speed)
+			}
+			if c.Wind.Gust != 0 {
+				wind += fmt.Sprintf("G%d", c.Wind.Gust)
+			}
+		}
+		ceiling := 0
+		if c.HasCeiling {
+			ceiling = c.CeilingFt
+		}
+		temporary := []string{}
+		for _, p := range c.Temporary {
+			temporary = append(temporary, p.Raw)
+		}
+		name := want.at.Format("021504Z")
+		if wind != want.wind || ceiling != want.ceilingFt || c.FlightCategory != want.category {
+			t.Errorf("%s: got %s, ceiling %d, %s, want %s, ceiling %d, %s", name, wind, ceiling, c.FlightCategory, want.wind, want.ceilingFt, want.category)
+		}
+		if got := strings.Join(temporary, ", "); got != want.temporary {
+			t.Errorf("%s temporary: got %q, want %q", name, got, want.temporary)
+		}
+	}
+	// Valid from 18Z up to but not including 18Z the next day
+	for _, outside := range []time.Time{at(18, 17), at(19, 18)} {
+		if _, err := f.ConditionsAt(outside); err == nil {
+			t.Errorf("Expected an error at %s", outside)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package taf
+
+import (
+	"github.com/cragcraig/flight/geo"
+)
+
+// A source of terminal aerodrome forecasts
+type Provider interface {
+	// Most recent forecast for each station issued within the time window
+	QueryStations(stations []string, hoursBeforeNow float64) ([]Taf, error)
+	// Most recent forecast for each station within radius statute miles
+	QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Taf, error)
+}
+
+// Provider used by the package level query functions
+var DefaultProvider Provider = NewAviationWeather()
@@ -0,0 +1,27 @@
+package taf
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/aviationweather"
+)
+
+type query struct {
+	aviationweather.Response
+	Taf []Taf `xml:"data>TAF"`
+}
+
+// Parses the XML result of a query to https://aviationweather.gov/api/data/taf
+// e.g., https://aviationweather.gov/api/data/taf?ids=KBJC&format=xml
+func unmarshalXml(xmlBody []byte) ([]Taf, error) {
+	var q query
+	if err := aviationweather.Unmarshal(xmlBody, "TAF", &q); err != nil {
+		return []Taf{}, err
+	}
+	// Check for obvious bad data
+	for _, t := range q.Taf {
+		if t.Longitude == 0 || t.Latitude == 0 {
+			return []Taf{}, fmt.Errorf("Invalid location returned from aviationweather.gov for %s", t.StationId)
+		}
+	}
+	return q.Taf, nil
+}
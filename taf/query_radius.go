@@ -0,0 +1,24 @@
+package taf
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"github.com/cragcraig/flight/parse"
+)
+
+// Most recent TAF for each station within radius statute miles
+func QueryRadius(coord geo.Coord, radius int, hoursBeforeNow float64) ([]Taf, error) {
+	if radius < 0 || radius > 500 {
+		return []Taf{}, errors.New("radius must be between 0 and 500 miles")
+	}
+	return DefaultProvider.QueryRadius(coord, radius, hoursBeforeNow)
+}
+
+func QueryStationRadius(natfix data.Natfix, station string, radius int, hoursBeforeNow float64) ([]Taf, error) {
+	c, err := parse.ParsePos(natfix, station)
+	if err != nil {
+		return []Taf{}, err
+	}
+	return QueryRadius(c, radius, hoursBeforeNow)
+}
@@ -0,0 +1,15 @@
+package taf
+
+import (
+	"errors"
+)
+
+func QueryStations(stations []string, hoursBeforeNow float64) ([]Taf, error) {
+	// stations should be 3 or 4 characters
+	for _, s := range stations {
+		if len(s) != 3 && len(s) != 4 {
+			return []Taf{}, errors.New("station ids must be valid station identifiers: " + s)
+		}
+	}
+	return DefaultProvider.QueryStations(stations, hoursBeforeNow)
+}
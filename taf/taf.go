@@ -0,0 +1,39 @@
+package taf
+
+import (
+	"github.com/cragcraig/flight/geo"
+	"time"
+)
+
+// XML fields documented at https://aviationweather.gov/data/api/
+type Taf struct {
+	RawText       string  `xml:"raw_text"`
+	StationId     string  `xml:"station_id"`
+	IssueTime     string  `xml:"issue_time"`
+	ValidTimeFrom string  `xml:"valid_time_from"`
+	ValidTimeTo   string  `xml:"valid_time_to"`
+	Longitude     float64 `xml:"longitude"`
+	Latitude      float64 `xml:"latitude"`
+	Elevation     float64 `xml:"elevation_m"`
+}
+
+func (t Taf) String() string {
+	return t.RawText
+}
+
+func (t Taf) Coord() geo.Coord {
+	return geo.NewCoord(t.Latitude, t.Longitude)
+}
+
+func (t Taf) IssuedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, t.IssueTime)
+}
+
+// Decodes the raw text into forecast periods
+func (t Taf) Forecast() (Forecast, error) {
+	ref, err := t.IssuedAt()
+	if err != nil {
+		ref = time.Now().UTC()
+	}
+	return Parse(t.RawText, ref)
+}
@@ -0,0 +1,192 @@
+package winds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"github.com/cragcraig/flight/geo"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Above this altitude temperatures are always negative and the sign is omitted
+const implied_negative_above_ft = 24000
+
+// Forecast winds and temperature at one altitude
+type Level struct {
+	AltFt         int
+	Dir, Speed    int // True degrees and knots
+	LightVariable bool
+	Temp          float64 // Celsius
+	HasTemp       bool
+}
+
+// Wind vector using the same convention as parse.ParseGeoVect, i.e., pointing
+// towards the direction the wind is blowing from.
+func (l Level) Wind() geo.Vect {
+	if l.LightVariable {
+		return geo.Vect{X: 0, Y: 0}
+	}
+	return geo.HeadingFromAngle(geo.Compass2Rad(float64(l.Dir))).Mult(float64(l.Speed))
+}
+
+type Station struct {
+	Id     string
+	Coord  geo.Coord
+	Levels []Level // Ascending altitude, levels below the station are omitted
+}
+
+// Parsed FB winds and temperatures aloft bulletin
+type Forecast struct {
+	Header   []string // Bulletin lines preceding the data table
+	Stations map[string]Station
+}
+
+// Parses an FB winds aloft bulletin, e.g.,
+//
+// DATA BASED ON 181200Z
+// VALID 181800Z   FOR USE 1400-2100Z. TEMPS NEG ABV 24000
+//
+// FT  3000    6000    9000   12000   18000   24000  30000  34000  39000
+// DEN              2619+06 2727-01 2839-14 2858-27 296541 297350 296059
+//
+// Station locations are resolved using natfix, unknown stations are skipped.
+func Parse(r io.Reader, natfix data.Natfix) (Forecast, error) {
+	f := Forecast{
+		Stations: make(map[string]Station),
+	}
+	s := bufio.NewScanner(r)
+	var altitudes []int
+	var columnEnds []int
+	for s.Scan() {
+		l := strings.TrimRight(s.Text(), " \r")
+		if altitudes == nil {
+			if strings.HasPrefix(l, "FT ") {
+				var err error
+				if altitudes, columnEnds, err = parseHeader(l); err != nil {
+					return Forecast{}, err
+				}
+			} else if len(strings.TrimSpace(l)) != 0 {
+				f.Header = append(f.Header, strings.TrimSpace(l))
+			}
+			continue
+		}
+		fields := strings.Fields(l)
+		if len(fields) < 2 {
+			continue
+		}
+		id := fields[0]
+		c, err := natfix.GetFix(id)
+		if err != nil {
+			continue
+		}
+		station := Station{Id: id, Coord: c}
+		start := len(id)
+		for i, end := range columnEnds {
+			if start >= len(l) {
+				break
+			}
+			group := strings.TrimSpace(l[start:minInt(end, len(l))])
+			start = end
+			if len(group) == 0 {
+				// Level is below the station
+				continue
+			}
+			if level, err := parseGroup(altitudes[i], group); err != nil {
+				return Forecast{}, fmt.Errorf("Error parsing FB line for %s: %s", id, err)
+			} else {
+				station.Levels = append(station.Levels, level)
+			}
+		}
+		f.Stations[id] = station
+	}
+	if err := s.Err(); err != nil {
+		return Forecast{}, errors.New("Error reading FB bulletin: " + err.Error())
+	}
+	if altitudes == nil {
+		return Forecast{}, errors.New("Error parsing FB bulletin: missing FT header line")
+	}
+	return f, nil
+}
+
+// Altitudes and the column at which each group ends
+func parseHeader(l string) ([]int, []int, error) {
+	altitudes := []int{}
+	ends := []int{}
+	for i := len("FT"); i < len(l); {
+		if l[i] == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(l) && l[j] != ' ' {
+			j++
+		}
+		alt, err := strconv.Atoi(l[i:j])
+		if err != nil {
+			return nil, nil, errors.New("Invalid FB altitude header: " + l)
+		}
+		altitudes = append(altitudes, alt)
+		ends = append(ends, j)
+		i = j
+	}
+	if len(altitudes) == 0 {
+		return nil, nil, errors.New("Empty FB altitude header: " + l)
+	}
+	return altitudes, ends, nil
+}
+
+// e.g., 2619, 2619+06, 296541, 9900, 7799
+func parseGroup(alt int, g string) (Level, error) {
+	e := errors.New("invalid group " + g)
+	if len(g) < 4 {
+		return Level{}, e
+	}
+	dd, errd := strconv.Atoi(g[:2])
+	ff, errf := strconv.Atoi(g[2:4])
+	if errd != nil || errf != nil {
+		return Level{}, e
+	}
+	level := Level{AltFt: alt}
+	if dd == 99 && ff == 0 {
+		level.LightVariable = true
+	} else {
+		if dd > 50 {
+			// Speeds of 100 kts or more add 50 to the direction
+			dd -= 50
+			ff += 100
+		}
+		level.Dir = dd * 10
+		level.Speed = ff
+	}
+
+	t := g[4:]
+	if len(t) == 0 {
+		return level, nil
+	}
+	sign := 1.0
+	if t[0] == '+' || t[0] == '-' {
+		if t[0] == '-' {
+			sign = -1
+		}
+		t = t[1:]
+	} else if alt > implied_negative_above_ft {
+		sign = -1
+	}
+	temp, err := strconv.Atoi(t)
+	if err != nil {
+		return Level{}, e
+	}
+	level.Temp = sign * float64(temp)
+	level.HasTemp = true
+	return level, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,129 @@
+package winds
+
+import (
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"strings"
+	"testing"
+)
+
+func natfixLine(id, lat, lon string) string {
+	return fmt.Sprintf("I %-5s %s %s 'ZDV  CO    %-7s", id, lat, lon, "VOR")
+}
+
+func testNatfix(t *testing.T) data.Natfix {
+	lines := []string{
+		natfixLine("DEN", "400000N", "1050000W"),
+		natfixLine("CYS", "410000N", "1050000W"),
+		natfixLine("GLD", "392000N", "1014200W"),
+		natfixLine("MKC", "390000N", "0950000W"),
+	}
+	natfix, err := data.ParseNatfix(strings.NewReader("NATFIX\n'20261018\n" + strings.Join(lines, "\n") + "\n$\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return natfix
+}
+
+// Trimmed from a real FB bulletin, with an unknown station
+const testBulletin = `000
+FBUS31 KWNO 181358
+FD1US1
+DATA BASED ON 181200Z
+VALID 181800Z   FOR USE 1400-2100Z. TEMPS NEG ABV 24000
+
+FT  3000    6000    9000   12000   18000   24000  30000  34000  39000
+DEN              2619+06 2727-01 2839-14 2858-27 296541 297350 296059
+CYS              2725+04 2733-03 2847-16 2865-29 771945 772154 771762
+GLD      9900+14 2312+14 2520+07 2637-10 2757-23 279037 279547 279656
+MKC 1809 2012+15 2316+10 2422+04 2535-09 2650-21 275236 276346 276357
+XXX 1809 2012+15 2316+10 2422+04 2535-09 2650-21 275236 276346 276357
+`
+
+func parseTestBulletin(t *testing.T) Forecast {
+	f, err := Parse(strings.NewReader(testBulletin), testNatfix(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func formatLevel(l Level) string {
+	s := fmt.Sprintf("%d %03d@%d", l.AltFt, l.Dir, l.Speed)
+	if l.LightVariable {
+		s = fmt.Sprintf("%d LV", l.AltFt)
+	}
+	if l.HasTemp {
+		s += fmt.Sprintf(" %+.0f", l.Temp)
+	}
+	return s
+}
+
+func TestParse(t *testing.T) {
+	f := parseTestBulletin(t)
+	if len(f.Header) != 5 || f.Header[4] != "VALID 181800Z   FOR USE 1400-2100Z. TEMPS NEG ABV 24000" {
+		t.Errorf("Header: got %q", f.Header)
+	}
+	if len(f.Stations) != 4 {
+		t.Errorf("Got %d stations, want all but the unknown station", len(f.Stations))
+	}
+	for id, want := range map[string][]string{
+		// Levels below the station are omitted
+		"DEN": {"9000 260@19 +6", "12000 270@27 -1", "18000 280@39 -14", "24000 280@58 -27",
+			"30000 290@65 -41", "34000 290@73 -50", "39000 290@60 -59"},
+		// 100 kts or more, encoded by adding 50 to the direction
+		"CYS": {"9000 270@25 +4", "12000 270@33 -3", "18000 280@47 -16", "24000 280@65 -29",
+			"30000 270@119 -45", "34000 270@121 -54", "39000 270@117 -62"},
+		// Light and variable
+		"GLD": {"6000 LV +14", "9000 230@12 +14", "12000 250@20 +7", "18000 260@37 -10", "24000 270@57 -23",
+			"30000 270@90 -37", "34000 270@95 -47", "39000 270@96 -56"},
+		// No temperature is forecast at 3000ft
+		"MKC": {"3000 180@9", "6000 200@12 +15", "9000 230@16 +10", "12000 240@22 +4", "18000 250@35 -9",
+			"24000 260@50 -21", "30000 270@52 -36", "34000 270@63 -46", "39000 270@63 -57"},
+	} {
+		got := []string{}
+		for _, l := range f.Stations[id].Levels {
+			got = append(got, formatLevel(l))
+		}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Errorf("%s: got %q\nwant %q", id, got, want)
+		}
+	}
+}
+
+func TestParseGroup(t *testing.T) {
+	for _, c := range []struct {
+		alt   int
+		group string
+		want  string // Empty if invalid
+	}{
+		{3000, "1809", "3000 180@9"},
+		{9000, "2619+06", "9000 260@19 +6"},
+		{9000, "9900+14", "9000 LV +14"},
+		{3000, "9900", "3000 LV"},
+		{30000, "780051", "30000 280@100 -51"},
+		{34000, "7799", "34000 270@199"},
+		{39000, "296059", "39000 290@60 -59"},
+		{18000, "2839-14", "18000 280@39 -14"},
+		{9000, "26", ""},
+		{9000, "26AB+06", ""},
+		{9000, "2619+X6", ""},
+	} {
+		l, err := parseGroup(c.alt, c.group)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%s: got %s, want an error", c.group, formatLevel(l))
+			}
+		} else if err != nil {
+			t.Errorf("%s: %s", c.group, err)
+		} else if got := formatLevel(l); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.group, got, c.want)
+		}
+	}
+}
+
+func TestParseMissingHeader(t *testing.T) {
+	if _, err := Parse(strings.NewReader("DEN 2619+06\n"), testNatfix(t)); err == nil {
+		t.Error("Expected an error without an FT header line")
+	}
+}
@@ -0,0 +1,120 @@
+package winds
+
+import (
+	"errors"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"sort"
+)
+
+// Number of nearest reporting stations blended horizontally
+const interpolation_stations = 4
+
+// Standard lapse rate, used to extend temperatures below the lowest report
+const lapse_rate_c_per_ft = 0.00198
+
+// Interpolated conditions aloft
+type Aloft struct {
+	Wind    geo.Vect // See Level.Wind
+	Temp    float64  // Celsius
+	HasTemp bool
+}
+
+// Wind and temperature at a position and altitude, interpolated linearly in
+// altitude at each of the nearest stations and then by inverse distance
+// squared between stations.
+func (f Forecast) At(c geo.Coord, altFt int) (Aloft, error) {
+	type candidate struct {
+		dist  float64
+		aloft Aloft
+	}
+	candidates := []candidate{}
+	for _, s := range f.Stations {
+		if a, ok := s.at(altFt); ok {
+			candidates = append(candidates, candidate{geo.GlobeDistNM(c, s.Coord), a})
+		}
+	}
+	if len(candidates) == 0 {
+		return Aloft{}, errors.New("No winds aloft stations report the requested altitude")
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].dist < candidates[j].dist
+	})
+	if candidates[0].dist < 1 {
+		return candidates[0].aloft, nil
+	}
+	if len(candidates) > interpolation_stations {
+		candidates = candidates[:interpolation_stations]
+	}
+
+	var wind geo.Vect
+	var temp, windWeights, tempWeights float64
+	for _, cand := range candidates {
+		w := 1 / (cand.dist * cand.dist)
+		wind = wind.Add(cand.aloft.Wind.Mult(w))
+		windWeights += w
+		if cand.aloft.HasTemp {
+			temp += cand.aloft.Temp * w
+			tempWeights += w
+		}
+	}
+	result := Aloft{Wind: wind.Mult(1 / windWeights)}
+	if tempWeights > 0 {
+		result.Temp = temp / tempWeights
+		result.HasTemp = true
+	}
+	return result, nil
+}
+
+// Conditions at a station, linearly interpolated between reported levels
+func (s Station) at(altFt int) (Aloft, bool) {
+	if len(s.Levels) == 0 {
+		return Aloft{}, false
+	}
+	alt := float64(altFt)
+	a := Aloft{}
+
+	// Wind, clamped to the lowest and highest reported levels
+	if altFt <= s.Levels[0].AltFt {
+		a.Wind = s.Levels[0].Wind()
+	} else if last := s.Levels[len(s.Levels)-1]; altFt >= last.AltFt {
+		a.Wind = last.Wind()
+	} else {
+		for i := 1; i < len(s.Levels); i++ {
+			lo, hi := s.Levels[i-1], s.Levels[i]
+			if altFt <= hi.AltFt {
+				t := (alt - float64(lo.AltFt)) / float64(hi.AltFt-lo.AltFt)
+				a.Wind = lo.Wind().Mult(1 - t).Add(hi.Wind().Mult(t))
+				break
+			}
+		}
+	}
+
+	// Temperature, extended using the standard lapse rate
+	temps := []Level{}
+	for _, l := range s.Levels {
+		if l.HasTemp {
+			temps = append(temps, l)
+		}
+	}
+	if len(temps) == 0 {
+		return a, true
+	}
+	a.HasTemp = true
+	if altFt <= temps[0].AltFt {
+		a.Temp = temps[0].Temp + lapse_rate_c_per_ft*float64(temps[0].AltFt-altFt)
+	} else if last := temps[len(temps)-1]; altFt >= last.AltFt {
+		a.Temp = last.Temp - lapse_rate_c_per_ft*float64(altFt-last.AltFt)
+	} else {
+		for i := 1; i < len(temps); i++ {
+			lo, hi := temps[i-1], temps[i]
+			if altFt <= hi.AltFt {
+				t := (alt - float64(lo.AltFt)) / float64(hi.AltFt-lo.AltFt)
+				a.Temp = lo.Temp*(1-t) + hi.Temp*t
+				break
+			}
+		}
+	}
+	a.Temp = math.Round(a.Temp*10) / 10
+	return a, true
+}
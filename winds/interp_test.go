@@ -0,0 +1,93 @@
+package winds
+
+import (
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"testing"
+)
+
+func checkAloft(t *testing.T, name string, got Aloft, wind geo.Vect, temp float64) {
+	if got.Wind.DistanceTo(wind) > 1e-9 {
+		t.Errorf("%s wind: got %+v, want %+v", name, got.Wind, wind)
+	}
+	if !got.HasTemp || math.Abs(got.Temp-temp) > 1e-9 {
+		t.Errorf("%s temperature: got %.2f (%t), want %.2f", name, got.Temp, got.HasTemp, temp)
+	}
+}
+
+func TestAtStationAltitudes(t *testing.T) {
+	f := parseTestBulletin(t)
+	den := f.Stations["DEN"]
+	at9000, at12000 := den.Levels[0], den.Levels[1]
+	for _, c := range []struct {
+		name  string
+		altFt int
+		wind  geo.Vect
+		temp  float64
+	}{
+		{"Reported level", 12000, at12000.Wind(), -1},
+		// Halfway between 2619+06 and 2727-01
+		{"Between levels", 10500, at9000.Wind().Add(at12000.Wind()).Mult(0.5), 2.5},
+		// The lowest wind, and the lowest temperature warmer at the standard lapse rate
+		{"Below the lowest level", 7000, at9000.Wind(), 10},
+		{"Above the highest level", 41000, den.Levels[len(den.Levels)-1].Wind(), -63},
+	} {
+		a, err := f.At(den.Coord, c.altFt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAloft(t, c.name, a, c.wind, c.temp)
+	}
+}
+
+func TestAtMissingTemperature(t *testing.T) {
+	f := parseTestBulletin(t)
+	mkc := f.Stations["MKC"]
+	// No temperature at 3000ft, so extended down from +15 at 6000ft
+	a, err := f.At(mkc.Coord, 3000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAloft(t, "MKC 3000ft", a, mkc.Levels[0].Wind(), 20.9)
+
+	// Winds between 3000ft and 6000ft, but temperatures only from 6000ft
+	a, err = f.At(mkc.Coord, 4500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAloft(t, "MKC 4500ft", a, mkc.Levels[0].Wind().Add(mkc.Levels[1].Wind()).Mult(0.5), 18)
+}
+
+func TestAtLightVariable(t *testing.T) {
+	f := parseTestBulletin(t)
+	gld := f.Stations["GLD"]
+	a, err := f.At(gld.Coord, 6000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAloft(t, "GLD 6000ft", a, geo.Vect{X: 0, Y: 0}, 14)
+	// Blends towards calm below 9000ft
+	a, err = f.At(gld.Coord, 7500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAloft(t, "GLD 7500ft", a, gld.Levels[1].Wind().Mult(0.5), 14)
+}
+
+func TestAtBetweenStations(t *testing.T) {
+	f := Forecast{Stations: map[string]Station{
+		"A": Station{Id: "A", Coord: geo.NewCoord(40, -106), Levels: []Level{{AltFt: 9000, Dir: 270, Speed: 20, Temp: 0, HasTemp: true}}},
+		"B": Station{Id: "B", Coord: geo.NewCoord(40, -104), Levels: []Level{{AltFt: 9000, Dir: 360, Speed: 10}}},
+	}}
+	a, err := f.At(geo.NewCoord(40, -105), 9000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Equally distant, and only A forecasts a temperature
+	want := f.Stations["A"].Levels[0].Wind().Add(f.Stations["B"].Levels[0].Wind()).Mult(0.5)
+	checkAloft(t, "Midway", a, want, 0)
+
+	if _, err := (Forecast{Stations: map[string]Station{}}).At(geo.NewCoord(40, -105), 9000); err == nil {
+		t.Error("Expected an error without any stations")
+	}
+}
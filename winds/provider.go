@@ -0,0 +1,73 @@
+package winds
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cragcraig/flight/data"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const local_bulletin = "FB.txt"
+
+const aviationWeatherUrl = "https://aviationweather.gov/api/data/windtemp?region=all&level=low&fcst=06"
+
+// A source of FB winds and temperatures aloft bulletins
+type Provider interface {
+	Bulletin() (io.ReadCloser, error)
+}
+
+// Provider used by Load when no local bulletin file exists
+var DefaultProvider Provider = NewAviationWeather()
+
+// Provider backed by the aviationweather.gov data API
+// See https://aviationweather.gov/data/api/
+type AviationWeather struct {
+	Url    string
+	Client *http.Client
+}
+
+func NewAviationWeather() AviationWeather {
+	return AviationWeather{
+		Url:    aviationWeatherUrl,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (a AviationWeather) Bulletin() (io.ReadCloser, error) {
+	resp, err := a.Client.Get(a.Url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Winds aloft service returned %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Parses a local FB bulletin file
+func LoadFile(fname string, natfix data.Natfix) (Forecast, error) {
+	file, err := os.Open(fname)
+	if err != nil {
+		return Forecast{}, errors.New("Unable to load winds aloft bulletin: " + err.Error())
+	}
+	defer file.Close()
+	return Parse(file, natfix)
+}
+
+// Parses the local FB.txt bulletin if present, otherwise fetches the current
+// bulletin from DefaultProvider
+func Load(natfix data.Natfix) (Forecast, error) {
+	if _, err := os.Stat(local_bulletin); err == nil {
+		return LoadFile(local_bulletin, natfix)
+	}
+	body, err := DefaultProvider.Bulletin()
+	if err != nil {
+		return Forecast{}, err
+	}
+	defer body.Close()
+	return Parse(body, natfix)
+}
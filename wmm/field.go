@@ -0,0 +1,118 @@
+package wmm
+
+import (
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"time"
+)
+
+// Geomagnetic reference radius, km
+const reference_radius_km = 6371.2
+
+// WGS-84 ellipsoid, km
+const wgs84_a_km = 6378.137
+const wgs84_f = 1 / 298.257223563
+
+const km_per_ft = 0.0003048
+
+// Keeps the east component finite at the geographic poles, where declination
+// is undefined
+const min_sin_colatitude = 1e-10
+
+// Magnetic field elements at a point
+type Elements struct {
+	X, Y, Z     float64 // North, east and down components, nT
+	H, F        float64 // Horizontal and total intensity, nT
+	Declination float64 // Degrees, east positive
+	Inclination float64 // Degrees, down positive
+}
+
+// Magnetic variation in degrees, west positive as in data.Apt, at an
+// altitude above mean sea level
+func (m Model) Variation(c geo.Coord, altFt float64, t time.Time) (float64, error) {
+	e, err := m.Elements(c, altFt*km_per_ft, DecimalYear(t))
+	if err != nil {
+		return math.NaN(), err
+	}
+	return -e.Declination, nil
+}
+
+// Field elements at a geodetic position and height above the WGS-84
+// ellipsoid, following the WMM technical report equations
+func (m Model) Elements(c geo.Coord, heightKm, year float64) (Elements, error) {
+	if err := m.checkValid(year); err != nil {
+		return Elements{}, err
+	}
+	dt := year - m.Epoch
+
+	// Geodetic to geocentric spherical coordinates
+	lat, lon := geo.Deg2Rad(c.Lat()), geo.Deg2Rad(c.Lon())
+	e2 := wgs84_f * (2 - wgs84_f)
+	rc := wgs84_a_km / math.Sqrt(1-e2*math.Sin(lat)*math.Sin(lat))
+	p := (rc + heightKm) * math.Cos(lat)
+	z := (rc*(1-e2) + heightKm) * math.Sin(lat)
+	r := math.Hypot(p, z)
+	latc := math.Asin(z / r)
+
+	// Colatitude cosine and sine
+	x, s := math.Sin(latc), math.Max(math.Cos(latc), min_sin_colatitude)
+	pnm, dpnm := legendre(m.degree, x, s)
+
+	var bx, by, bz float64
+	ratio := reference_radius_km / r
+	scale := ratio * ratio
+	for n := 1; n <= m.degree; n++ {
+		scale *= ratio // (a/r)^(n+2)
+		for o := 0; o <= n; o++ {
+			g := m.g[n][o] + dt*m.gDot[n][o]
+			h := m.h[n][o] + dt*m.hDot[n][o]
+			sinML, cosML := math.Sincos(float64(o) * lon)
+			gh := g*cosML + h*sinML
+			bx += scale * gh * dpnm[n][o]
+			by += scale * float64(o) * (g*sinML - h*cosML) * pnm[n][o] / s
+			bz -= scale * float64(n+1) * gh * pnm[n][o]
+		}
+	}
+
+	// Rotate from geocentric to geodetic
+	sinD, cosD := math.Sincos(latc - lat)
+	e := Elements{
+		X: bx*cosD - bz*sinD,
+		Y: by,
+		Z: bx*sinD + bz*cosD,
+	}
+	e.H = math.Hypot(e.X, e.Y)
+	e.F = math.Hypot(e.H, e.Z)
+	e.Declination = geo.Rad2Deg(math.Atan2(e.Y, e.X))
+	e.Inclination = geo.Rad2Deg(math.Atan2(e.Z, e.H))
+	return e, nil
+}
+
+// Schmidt semi-normalized associated Legendre functions of cos(colatitude),
+// x, and their derivatives with respect to colatitude, given its sine s
+func legendre(degree int, x, s float64) ([][]float64, [][]float64) {
+	p, dp := triangular(degree), triangular(degree)
+	p[0][0] = 1
+	for n := 1; n <= degree; n++ {
+		// Sectoral terms
+		if n == 1 {
+			p[1][1], dp[1][1] = s, x
+		} else {
+			k := math.Sqrt(float64(2*n-1) / float64(2*n))
+			p[n][n] = k * s * p[n-1][n-1]
+			dp[n][n] = k * (x*p[n-1][n-1] + s*dp[n-1][n-1])
+		}
+		for o := 0; o < n; o++ {
+			k1 := float64(2*n - 1)
+			k2 := math.Sqrt(float64((n-1)*(n-1) - o*o))
+			k3 := math.Sqrt(float64(n*n - o*o))
+			var p2, dp2 float64
+			if n-2 >= o {
+				p2, dp2 = p[n-2][o], dp[n-2][o]
+			}
+			p[n][o] = (k1*x*p[n-1][o] - k2*p2) / k3
+			dp[n][o] = (k1*(x*dp[n-1][o]-s*p[n-1][o]) - k2*dp2) / k3
+		}
+	}
+	return p, dp
+}
@@ -0,0 +1,142 @@
+package wmm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// World Magnetic Model coefficient file, as published by NOAA NCEI
+// https://www.ncei.noaa.gov/products/world-magnetic-model
+const coefficient_file = "WMM.COF"
+
+// Each model is valid for five years from its epoch
+const validity_years = 5
+
+// Spherical harmonic model of the main geomagnetic field
+type Model struct {
+	Name     string
+	Epoch    float64 // Decimal year
+	Released string
+	degree   int
+	// Gauss coefficients (nT) and their secular variation (nT/year), indexed
+	// by degree n then order m
+	g, h, gDot, hDot [][]float64
+}
+
+// Parses the WMM.COF coefficient file in the working directory
+func Load() (Model, error) {
+	file, err := os.Open(coefficient_file)
+	if err != nil {
+		return Model{}, errors.New("Unable to load World Magnetic Model: " + err.Error())
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Parses a coefficient file in the published format: a header line with the
+// epoch, model name and release date, then one line per degree n and order m
+//
+//	n m g h gDot hDot
+//
+// terminated by a line of 9s
+func Parse(r io.Reader) (Model, error) {
+	s := bufio.NewScanner(r)
+	if !s.Scan() {
+		return Model{}, errors.New("Error parsing WMM coefficients: Missing header")
+	}
+	header := strings.Fields(s.Text())
+	if len(header) < 2 {
+		return Model{}, errors.New("Error parsing WMM coefficients: Invalid header: " + s.Text())
+	}
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return Model{}, errors.New("Error parsing WMM coefficients: Invalid epoch: " + header[0])
+	}
+	m := Model{Name: header[1], Epoch: epoch}
+	if len(header) > 2 {
+		m.Released = header[2]
+	}
+
+	type term struct {
+		n, m             int
+		g, h, gDot, hDot float64
+	}
+	terms := []term{}
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(l, "9999") {
+			break
+		} else if len(l) == 0 {
+			continue
+		}
+		fields := strings.Fields(l)
+		if len(fields) != 6 {
+			return Model{}, errors.New("Error parsing WMM coefficients: Invalid line: " + l)
+		}
+		t := term{}
+		n, errn := strconv.Atoi(fields[0])
+		o, erro := strconv.Atoi(fields[1])
+		vals := []*float64{&t.g, &t.h, &t.gDot, &t.hDot}
+		for i, v := range vals {
+			if *v, err = strconv.ParseFloat(fields[i+2], 64); err != nil {
+				break
+			}
+		}
+		if errn != nil || erro != nil || err != nil || n < 1 || o < 0 || o > n {
+			return Model{}, errors.New("Error parsing WMM coefficients: Invalid line: " + l)
+		}
+		t.n, t.m = n, o
+		terms = append(terms, t)
+		if n > m.degree {
+			m.degree = n
+		}
+	}
+	if err := s.Err(); err != nil {
+		return Model{}, errors.New("Error parsing WMM coefficients: " + err.Error())
+	}
+	if len(terms) == 0 {
+		return Model{}, errors.New("Error parsing WMM coefficients: No coefficients")
+	}
+
+	m.g, m.h = triangular(m.degree), triangular(m.degree)
+	m.gDot, m.hDot = triangular(m.degree), triangular(m.degree)
+	for _, t := range terms {
+		m.g[t.n][t.m], m.h[t.n][t.m] = t.g, t.h
+		m.gDot[t.n][t.m], m.hDot[t.n][t.m] = t.gDot, t.hDot
+	}
+	return m, nil
+}
+
+func triangular(degree int) [][]float64 {
+	t := make([][]float64, degree+1)
+	for n := range t {
+		t[n] = make([]float64, n+1)
+	}
+	return t
+}
+
+// Last date the model is valid for, as a decimal year
+func (m Model) Expires() float64 {
+	return m.Epoch + validity_years
+}
+
+func (m Model) checkValid(year float64) error {
+	if year < m.Epoch || year > m.Expires() {
+		return fmt.Errorf("%s is only valid from %.1f to %.1f, not %.2f", m.Name, m.Epoch, m.Expires(), year)
+	}
+	return nil
+}
+
+// e.g., 2022.5 for midday on July 2nd 2022
+func DecimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(t.Year()) + float64(t.Sub(start))/float64(end.Sub(start))
+}
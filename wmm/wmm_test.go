@@ -0,0 +1,202 @@
+package wmm
+
+import (
+	"bufio"
+	"github.com/cragcraig/flight/geo"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Published WMM2020 coefficients and the test values distributed with them
+// by NOAA NCEI, both public domain
+const (
+	wmm2020_file        = "testdata/WMM2020.COF"
+	wmm2020_test_values = "testdata/WMM2020_TEST_VALUES.txt"
+)
+
+// Test values are published to hundredths of a degree and tenths of a nT
+const (
+	official_angle_tolerance     = 0.01
+	official_intensity_tolerance = 0.1
+)
+
+// Degree 1 coefficients in the published format, for which the field has a
+// closed form
+const dipole_file = "testdata/DIPOLE.COF"
+
+const (
+	dipole_g10, dipole_g10_dot = -29351.8, 12.0
+	dipole_g11, dipole_g11_dot = -1410.8, 9.7
+	dipole_h11, dipole_h11_dot = 4545.4, -21.5
+)
+
+const angle_tolerance = 1e-6 // Degrees
+
+func loadDipole(t *testing.T) Model {
+	return loadModel(t, dipole_file)
+}
+
+func loadModel(t *testing.T, path string) Model {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	m, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// Expected elements from the dipole potential at a point where the geocentric
+// and geodetic verticals coincide, given the north, east and down components
+// in units of (a/r)^3
+func dipoleElements(x, y, z, r float64) (float64, float64) {
+	k := math.Pow(reference_radius_km/r, 3)
+	x, y, z = k*x, k*y, k*z
+	return geo.Rad2Deg(math.Atan2(y, x)), geo.Rad2Deg(math.Atan2(z, math.Hypot(x, y)))
+}
+
+func checkElements(t *testing.T, name string, e Elements, declination, inclination float64) {
+	if math.Abs(e.Declination-declination) > angle_tolerance {
+		t.Errorf("%s declination: got %.6f, want %.6f", name, e.Declination, declination)
+	}
+	if math.Abs(e.Inclination-inclination) > angle_tolerance {
+		t.Errorf("%s inclination: got %.6f, want %.6f", name, e.Inclination, inclination)
+	}
+}
+
+func TestParse(t *testing.T) {
+	m := loadDipole(t)
+	if m.Name != "DIPOLE-TEST" || m.Epoch != 2025 || m.Released != "01/01/2025" {
+		t.Errorf("Header: got %q, %.1f, %q", m.Name, m.Epoch, m.Released)
+	}
+	if m.degree != 1 || m.g[1][0] != dipole_g10 || m.h[1][1] != dipole_h11 || m.hDot[1][1] != dipole_h11_dot {
+		t.Errorf("Coefficients: got degree %d, g %v, h %v", m.degree, m.g, m.h)
+	}
+	if m.Expires() != 2030 {
+		t.Errorf("Expires: got %.1f", m.Expires())
+	}
+}
+
+func TestParseWmm2020(t *testing.T) {
+	m := loadModel(t, wmm2020_file)
+	if m.Name != "WMM-2020" || m.Epoch != 2020 || m.Released != "12/10/2019" || m.degree != 12 {
+		t.Errorf("Header: got %q, %.1f, %q, degree %d", m.Name, m.Epoch, m.Released, m.degree)
+	}
+	if m.g[1][0] != -29404.5 || m.h[1][1] != 4652.9 || m.gDot[12][12] != -0.1 {
+		t.Errorf("Coefficients: got g10 %.1f, h11 %.1f, g12,12 dot %.1f", m.g[1][0], m.h[1][1], m.gDot[12][12])
+	}
+}
+
+// Each line is the decimal year, height above the ellipsoid in km, geodetic
+// latitude and longitude, then D, I, H, X, Y, Z and F followed by their
+// rates of change, which aren't modeled here
+func TestOfficialValues(t *testing.T) {
+	m := loadModel(t, wmm2020_file)
+	file, err := os.Open(wmm2020_test_values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	s := bufio.NewScanner(file)
+	n := 0
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if len(l) == 0 || l[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(l)
+		if len(fields) != 18 {
+			t.Fatalf("Invalid test value line: %s", l)
+		}
+		v := make([]float64, 11)
+		for i := range v {
+			if v[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+				t.Fatalf("Invalid test value line: %s", l)
+			}
+		}
+		year, heightKm, c := v[0], v[1], geo.NewCoord(v[2], v[3])
+		e, err := m.Elements(c, heightKm, year)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := strings.Join(fields[:4], " ")
+		for i, check := range []struct {
+			element        string
+			got, tolerance float64
+		}{
+			{"D", e.Declination, official_angle_tolerance},
+			{"I", e.Inclination, official_angle_tolerance},
+			{"H", e.H, official_intensity_tolerance},
+			{"X", e.X, official_intensity_tolerance},
+			{"Y", e.Y, official_intensity_tolerance},
+			{"Z", e.Z, official_intensity_tolerance},
+			{"F", e.F, official_intensity_tolerance},
+		} {
+			if want := v[4+i]; math.Abs(check.got-want) > check.tolerance {
+				t.Errorf("%s %s: got %.3f, want %.2f", name, check.element, check.got, want)
+			}
+		}
+		n++
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 {
+		t.Errorf("Got %d test values, want all 100", n)
+	}
+}
+
+func TestElementsEquator(t *testing.T) {
+	m := loadDipole(t)
+	year := 2027.5
+	dt := year - m.Epoch
+	g10 := dipole_g10 + dt*dipole_g10_dot
+	g11 := dipole_g11 + dt*dipole_g11_dot
+	h11 := dipole_h11 + dt*dipole_h11_dot
+
+	// On the equator X = -g10, Y = g11 sin(lon) - h11 cos(lon) and
+	// Z = -2 (g11 cos(lon) + h11 sin(lon))
+	for _, lon := range []float64{0, 90, -135} {
+		sinL, cosL := math.Sincos(geo.Deg2Rad(lon))
+		e, err := m.Elements(geo.NewCoord(0, lon), 0, year)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, i := dipoleElements(-g10, g11*sinL-h11*cosL, -2*(g11*cosL+h11*sinL), wgs84_a_km)
+		checkElements(t, "Equator", e, d, i)
+	}
+}
+
+func TestElementsPole(t *testing.T) {
+	m := loadDipole(t)
+	e, err := m.Elements(geo.NewCoord(90, 0), 0, m.Epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// At the pole X = g11, Y = -h11 and Z = -2 g10, at the polar radius
+	d, i := dipoleElements(dipole_g11, -dipole_h11, -2*dipole_g10, wgs84_a_km*(1-wgs84_f))
+	checkElements(t, "Pole", e, d, i)
+}
+
+func TestVariation(t *testing.T) {
+	m := loadDipole(t)
+	at := time.Date(2026, 7, 2, 12, 0, 0, 0, time.UTC)
+	v, err := m.Variation(geo.NewCoord(0, 0), 0, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := m.Elements(geo.NewCoord(0, 0), 0, DecimalYear(at))
+	if v != -e.Declination {
+		t.Errorf("Variation: got %.6f, want west positive %.6f", v, -e.Declination)
+	}
+	if _, err := m.Variation(geo.NewCoord(0, 0), 0, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("Expected an error after the model expires")
+	}
+}